@@ -17,13 +17,15 @@ package main
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Введите токен в формате: Bearer <your-token>
+// @description Введите токен в формате: Bearer <your-token> или API-ключ в формате: ApiKey <your-key>
 import (
 	"net/http"
 	"strings"
+	"time"
 	"userManagement/internal/config"
 	"userManagement/internal/middleware"
 	"userManagement/internal/routes"
+	"userManagement/internal/services"
 	"userManagement/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -40,6 +42,9 @@ func main() {
 	// Подключаем БД
 	config.InitDB()
 
+	// Загружаем список отозванных токенов и периодически синхронизируем его с БД
+	services.StartRevocationSync(time.Minute)
+
 	// Создаём Gin-роутер
 	r := gin.Default()
 
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"userManagement/internal/keyring"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Локальный провайдер OpenID Connect для проверки входа через SSO без настоящего IdP.
+// Страница входа не спрашивает пароль: пользователь и его группы задаются в форме
+// (по умолчанию из флагов), а с -auto код выдается сразу, без формы.
+// Ключ подписи генерируется при запуске, поэтому после перезапуска выданные токены недействительны.
+
+const keyID = "mockidp"
+
+type user struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+type authorization struct {
+	User          user
+	RedirectURI   string
+	Nonce         string
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+type server struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	auto         bool
+	defaultUser  user
+	key          *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]authorization
+	access map[string]user
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Mock IdP</title></head>
+<body>
+<h1>Mock IdP</h1>
+<form method="post">
+{{range $name, $values := .Query}}{{range $values}}<input type="hidden" name="{{$name}}" value="{{.}}">{{end}}{{end}}
+<p><label>sub <input name="login_sub" value="{{.User.Subject}}"></label></p>
+<p><label>email <input name="login_email" value="{{.User.Email}}"></label></p>
+<p><label><input type="checkbox" name="login_email_verified" value="true"{{if .User.EmailVerified}} checked{{end}}> email подтвержден</label></p>
+<p><label>имя <input name="login_name" value="{{.User.Name}}"></label></p>
+<p><label>группы через запятую <input name="login_groups" value="{{.Groups}}"></label></p>
+<p><button type="submit">Войти</button></p>
+</form>
+</body></html>`))
+
+func main() {
+	addr := flag.String("addr", ":9000", "адрес HTTP-сервера")
+	issuer := flag.String("issuer", "http://localhost:9000", "идентификатор провайдера (iss), должен совпадать с адресом, по которому к нему обращается сервис")
+	clientID := flag.String("client-id", "user-management", "client_id сервиса")
+	clientSecret := flag.String("client-secret", "mock-secret", "секрет клиента, пустое значение — публичный клиент")
+	auto := flag.Bool("auto", false, "выдавать код без формы входа, для пользователя из флагов")
+	sub := flag.String("sub", "mock-1", "sub пользователя по умолчанию")
+	email := flag.String("email", "staff@example.com", "email пользователя по умолчанию")
+	emailVerified := flag.Bool("email-verified", true, "признак email_verified")
+	name := flag.String("name", "Mock Staff", "имя пользователя по умолчанию")
+	groups := flag.String("groups", "staff", "группы пользователя по умолчанию через запятую")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Не удалось создать ключ подписи: %v", err)
+	}
+
+	s := &server{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		auto:         *auto,
+		defaultUser: user{
+			Subject:       *sub,
+			Email:         *email,
+			EmailVerified: *emailVerified,
+			Name:          *name,
+			Groups:        splitList(*groups),
+		},
+		key:    key,
+		codes:  map[string]authorization{},
+		access: map[string]user{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/userinfo", s.userinfo)
+
+	log.Printf("Mock IdP %s слушает %s, client_id=%s", s.issuer, *addr, s.clientID)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"userinfo_endpoint":                     s.issuer + "/userinfo",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "profile", "email", "groups"},
+	})
+}
+
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	public := s.key.PublicKey
+	writeJSON(w, http.StatusOK, keyring.JWKSet{Keys: []keyring.JWK{{
+		Kty: "RSA",
+		Kid: keyID,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}}})
+}
+
+// authorize показывает форму входа (GET) и выдает код (POST или GET с -auto)
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Form.Get("client_id") != s.clientID {
+		http.Error(w, "неизвестный client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(r.Form.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "некорректный redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	fail := func(code, description string) {
+		query := redirectURI.Query()
+		query.Set("error", code)
+		query.Set("error_description", description)
+		query.Set("state", r.Form.Get("state"))
+		redirectURI.RawQuery = query.Encode()
+		http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+	}
+
+	if r.Form.Get("response_type") != "code" {
+		fail("unsupported_response_type", "поддерживается только code")
+		return
+	}
+	if r.Form.Get("code_challenge") == "" || r.Form.Get("code_challenge_method") != "S256" {
+		fail("invalid_request", "требуется PKCE S256")
+		return
+	}
+
+	u := s.defaultUser
+	if r.Method == http.MethodPost {
+		u = user{
+			Subject:       r.PostForm.Get("login_sub"),
+			Email:         r.PostForm.Get("login_email"),
+			EmailVerified: r.PostForm.Get("login_email_verified") == "true",
+			Name:          r.PostForm.Get("login_name"),
+			Groups:        splitList(r.PostForm.Get("login_groups")),
+		}
+		if u.Subject == "" {
+			http.Error(w, "sub обязателен", http.StatusBadRequest)
+			return
+		}
+	} else if !s.auto {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := loginPage.Execute(w, map[string]interface{}{
+			"Query":  r.URL.Query(),
+			"User":   u,
+			"Groups": strings.Join(u.Groups, ","),
+		}); err != nil {
+			log.Printf("Ошибка отрисовки формы входа: %v", err)
+		}
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		User:          u,
+		RedirectURI:   r.Form.Get("redirect_uri"),
+		Nonce:         r.Form.Get("nonce"),
+		CodeChallenge: r.Form.Get("code_challenge"),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+
+	log.Printf("Выдан код для %s (%s), группы %v", u.Subject, u.Email, u.Groups)
+
+	query := redirectURI.Query()
+	query.Set("code", code)
+	query.Set("state", r.Form.Get("state"))
+	redirectURI.RawQuery = query.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.clientSecret)) != 1 {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	auth, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || time.Now().After(auth.ExpiresAt) ||
+		auth.RedirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != auth.CodeChallenge {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.issuer,
+		"sub":            auth.User.Subject,
+		"aud":            s.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"email":          auth.User.Email,
+		"email_verified": auth.User.EmailVerified,
+		"name":           auth.User.Name,
+		"groups":         auth.User.Groups,
+	}
+	if auth.Nonce != "" {
+		claims["nonce"] = auth.Nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	accessToken := randomString()
+	s.mu.Lock()
+	s.access[accessToken] = auth.User
+	s.mu.Unlock()
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *server) userinfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	u, ok := s.access[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	s.mu.Unlock()
+	if !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sub":            u.Subject,
+		"email":          u.Email,
+		"email_verified": u.EmailVerified,
+		"name":           u.Name,
+		"groups":         u.Groups,
+	})
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("Ошибка генератора случайных чисел: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Ошибка записи ответа: %v", err)
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"userManagement/internal/config"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+)
+
+// Проверка целостности журнала активности из командной строки.
+// Завершается с кодом 1, если цепочка хэшей нарушена.
+func main() {
+	utils.InitLogger()
+	config.InitDB()
+
+	report, err := services.VerifyActivityChain()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка проверки журнала активности: %v\n", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("Проверено записей: %d, записей до появления цепочки: %d\n", report.Checked, report.Legacy)
+
+	if !report.Valid {
+		fmt.Printf("Цепочка нарушена на записи ID=%d: %s\n", *report.BrokenAtID, report.Reason)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Цепочка цела, хэш последней записи: %s\n", report.HeadHash)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// legacyActivityActions — начала текстовых описаний действий, которые журнал записывал до появления кодов действий
+var legacyActivityActions = []struct {
+	prefix string
+	action models.ActionCode
+}{
+	{"Завершил все свои сессии", models.ActionAuthLogoutAll},
+	{"Завершил все сессии пользователя", models.ActionUserSessionsRevoked},
+	{"Создал пользователя", models.ActionUserCreated},
+	{"Обновил пользователя", models.ActionUserUpdated},
+	{"Удалил пользователя", models.ActionUserDeleted},
+	{"Обновил роль пользователя", models.ActionUserRoleChanged},
+	{"Заблокировал пользователя", models.ActionUserBanned},
+	{"Разблокировал пользователя", models.ActionUserUnbanned},
+	{"Создал роль", models.ActionRoleCreated},
+	{"Обновил роль:", models.ActionRoleUpdated},
+	{"Удалил роль", models.ActionRoleDeleted},
+	{"Изменил права роли", models.ActionRolePermissions},
+	{"Создана группа", models.ActionGroupCreated},
+	{"Обновлена группа", models.ActionGroupUpdated},
+	{"Удалил группу", models.ActionGroupDeleted},
+	{"Добавлен пользователь", models.ActionGroupMemberAdded},
+	{"Удален пользователь", models.ActionGroupMemberRemoved},
+}
+
+// migrateLegacyActivityActions заменяет текстовые описания в старых записях журнала кодами действий,
+// чтобы по ним работал фильтр action. Исходный текст сохраняется в changes.legacy_action.
+// Меняются только записи без хэша: они созданы до появления цепочки и в нее не входят.
+// Записи с нераспознанным текстом остаются как есть.
+func migrateLegacyActivityActions(db *gorm.DB) error {
+	for _, legacy := range legacyActivityActions {
+		if err := db.Model(&models.ActivityLog{}).
+			Where("(hash IS NULL OR hash = '') AND action LIKE ?", legacy.prefix+"%").
+			Updates(map[string]interface{}{
+				"action":  legacy.action,
+				"changes": gorm.Expr("json_build_object('legacy_action', json_build_object('from', NULL, 'to', action))"),
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,15 +6,30 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"os"
+	"time"
 	"userManagement/internal/models"
 	"userManagement/internal/seed"
 	"userManagement/internal/utils"
 )
 
+// minJWTSecretLength — минимальная длина JWT_SECRET в байтах. Секретом подписываются токены второго шага входа,
+// ссылки подтверждения email и состояние входа через SSO, поэтому он обязателен при любом режиме ключей
+const minJWTSecretLength = 32
+
 // Глобальные переменные
 var (
 	DB        *gorm.DB
 	JWTSecret []byte
+
+	// Время жизни access- и refresh-токенов
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Время жизни ссылки для сброса пароля
+	PasswordResetTTL time.Duration
+
+	// Время жизни токена входа от имени пользователя
+	ImpersonationTTL time.Duration
 )
 
 // Инициализируем подключение к БД
@@ -26,6 +41,22 @@ func InitDB() {
 
 	// Загружаем JWT из .ENV
 	JWTSecret = []byte(os.Getenv("JWT_SECRET"))
+	if len(JWTSecret) < minJWTSecretLength {
+		utils.Log.Fatalf("JWT_SECRET должен содержать не менее %d байт", minJWTSecretLength)
+	}
+	AccessTokenTTL = getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	RefreshTokenTTL = getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	PasswordResetTTL = getDurationEnv("PASSWORD_RESET_TTL", time.Hour)
+	ImpersonationTTL = getDurationEnv("IMPERSONATION_TTL", 15*time.Minute)
+
+	initKeys()
+	initMailer()
+	initEmailVerification()
+	initPasswordPolicy()
+	initTwoFactor()
+	initLoginLockout()
+	initOIDC()
+	initSSO()
 
 	// Формируем строку подключения к БД с параметрами из .ENV
 	dsn := fmt.Sprintf(
@@ -46,22 +77,91 @@ func InitDB() {
 	// Сохраняем подключение в глобальной переменной
 	DB = db
 
+	// Колонка появилась вместе с подтверждением email: уже зарегистрированные пользователи считаются подтвержденными
+	markExistingVerified := !db.Migrator().HasColumn(&models.User{}, "email_verified")
+	// Обязательная 2FA для встроенных ролей с расширенными правами включается один раз при появлении колонки.
+	// Дальше флаг меняет только администратор, и перезапуск его решение не перезаписывает
+	requireBuiltin2FA := !db.Migrator().HasColumn(&models.Role{}, "require_2fa")
+	// Самостоятельная регистрация остается открытой только в организации по умолчанию, где она работала и раньше
+	openDefaultRegistration := !db.Migrator().HasColumn(&models.Organization{}, "registration_open")
+
+	// Членство в группах хранит роль участника, поэтому связь users-groups идет через модель GroupMember
+	if err := db.SetupJoinTable(&models.Group{}, "Users", &models.GroupMember{}); err != nil {
+		utils.Log.Fatalf("Ошибка настройки связи групп и пользователей: %v", err)
+	}
+	if err := db.SetupJoinTable(&models.User{}, "Groups", &models.GroupMember{}); err != nil {
+		utils.Log.Fatalf("Ошибка настройки связи групп и пользователей: %v", err)
+	}
+
 	// Автоматическая миграция таблицы users
 	errDB = db.AutoMigrate(
+		&models.Organization{},
 		&models.User{},
 		&models.Role{},
+		&models.Permission{},
 		&models.Group{},
+		&models.GroupMember{},
 		&models.ActivityLog{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.LoginIPFailure{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.OAuthClient{},
+		&models.AuthorizationCode{},
+		&models.OAuthConsent{},
+		&models.ExternalIdentity{},
+		&models.APIKey{},
+		&models.Session{},
 	)
 	if errDB != nil {
 		utils.Log.Fatalf("Ошибка миграции: %v", errDB)
 	}
 
-	if err := seed.SeedRoles(DB); err != nil {
+	if markExistingVerified {
+		if err := db.Model(&models.User{}).Where("1 = 1").
+			Updates(map[string]interface{}{"email_verified": true, "email_verified_at": time.Now()}).Error; err != nil {
+			utils.Log.Fatalf("Ошибка при отметке существующих пользователей как подтвержденных: %v", err)
+		}
+	}
+
+	if requireBuiltin2FA {
+		if err := db.Model(&models.Role{}).Where("name IN ?", []string{models.RoleAdmin, models.RoleModerator}).
+			Update("require_2fa", true).Error; err != nil {
+			utils.Log.Fatalf("Ошибка при включении обязательной 2FA для встроенных ролей: %v", err)
+		}
+	}
+
+	if openDefaultRegistration {
+		if err := db.Model(&models.Organization{}).Where("slug = ?", models.DefaultOrganizationSlug).
+			Update("registration_open", true).Error; err != nil {
+			utils.Log.Fatalf("Ошибка при открытии регистрации в организации по умолчанию: %v", err)
+		}
+	}
+
+	organization, err := seed.SeedDefaultOrganization(DB)
+	if err != nil {
+		utils.Log.Fatalf("Ошибка при сидировании организации: %v", err)
+	}
+	DefaultOrganizationID = organization.ID
+
+	if err := migrateToOrganizations(DB, organization.ID); err != nil {
+		utils.Log.Fatalf("Ошибка при переносе данных в организацию по умолчанию: %v", err)
+	}
+
+	if err := migrateLegacyActivityActions(DB); err != nil {
+		utils.Log.Fatalf("Ошибка при переводе старых записей журнала на коды действий: %v", err)
+	}
+
+	if err := seed.SeedRoles(DB, organization.ID); err != nil {
 		utils.Log.Fatalf("Ошибка при сидировании ролей: %v", err)
 	}
 
-	if err := seed.SeedAdmin(DB); err != nil {
+	if err := seed.SeedPermissions(DB); err != nil {
+		utils.Log.Fatalf("Ошибка при сидировании прав: %v", err)
+	}
+
+	if err := seed.SeedAdmin(DB, organization.ID); err != nil {
 		utils.Log.Fatalf("Ошибка при сидировании админа: %v", err)
 	}
 
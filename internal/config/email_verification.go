@@ -0,0 +1,37 @@
+package config
+
+import (
+	"time"
+	"userManagement/internal/utils"
+)
+
+// Политики доступа для пользователей с неподтвержденным email
+const (
+	// EmailPolicyOff — подтверждение email не влияет на доступ
+	EmailPolicyOff = "off"
+	// EmailPolicyBlockLogin — вход запрещен до подтверждения email
+	EmailPolicyBlockLogin = "block_login"
+	// EmailPolicyLimited — вход разрешен, но эндпоинты, требующие прав, недоступны
+	EmailPolicyLimited = "limited"
+)
+
+var (
+	// EmailVerificationPolicy определяет ограничения для пользователей с неподтвержденным email
+	EmailVerificationPolicy string
+
+	// Время жизни ссылки для подтверждения email
+	EmailVerificationTTL time.Duration
+)
+
+// initEmailVerification читает политику подтверждения email из переменных окружения
+func initEmailVerification() {
+	EmailVerificationTTL = getDurationEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+
+	switch policy := getEnv("EMAIL_VERIFICATION_POLICY", EmailPolicyOff); policy {
+	case EmailPolicyOff, EmailPolicyBlockLogin, EmailPolicyLimited:
+		EmailVerificationPolicy = policy
+	default:
+		utils.Log.Warnf("Некорректное значение EMAIL_VERIFICATION_POLICY=%q, используется %s", policy, EmailPolicyOff)
+		EmailVerificationPolicy = EmailPolicyOff
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+	"userManagement/internal/utils"
+)
+
+// getEnv читает переменную окружения, возвращая значение по умолчанию, если она не задана
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDurationEnv читает длительность из переменной окружения, возвращая значение по умолчанию при ошибке
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		utils.Log.Warnf("Некорректное значение %s=%q, используется %s", key, value, fallback)
+		return fallback
+	}
+
+	return duration
+}
+
+// getIntEnv читает целое число из переменной окружения, возвращая значение по умолчанию при ошибке
+func getIntEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		utils.Log.Warnf("Некорректное значение %s=%q, используется %d", key, value, fallback)
+		return fallback
+	}
+
+	return number
+}
+
+// getBoolEnv читает логическое значение из переменной окружения, возвращая значение по умолчанию при ошибке
+func getBoolEnv(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		utils.Log.Warnf("Некорректное значение %s=%q, используется %t", key, value, fallback)
+		return fallback
+	}
+
+	return flag
+}
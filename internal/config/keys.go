@@ -0,0 +1,28 @@
+package config
+
+import (
+	"userManagement/internal/keyring"
+	"userManagement/internal/utils"
+)
+
+// Keys подписывает и проверяет access-токены
+var Keys *keyring.Keyring
+
+// initKeys загружает ключи подписи из JWT_KEYS_DIR.
+// Если каталог не задан, токены подписываются алгоритмом HS256 секретом JWT_SECRET.
+func initKeys() {
+	dir := getEnv("JWT_KEYS_DIR", "")
+	if dir == "" {
+		utils.Log.Warn("JWT_KEYS_DIR не задан, токены подписываются общим секретом HS256")
+		Keys = keyring.NewHMAC(JWTSecret)
+		return
+	}
+
+	ring, err := keyring.LoadDir(dir, getEnv("JWT_ACTIVE_KID", ""))
+	if err != nil {
+		utils.Log.Fatalf("Ошибка загрузки ключей подписи JWT: %v", err)
+	}
+
+	Keys = ring
+	utils.Log.Infof("Ключи подписи JWT загружены из %s, активный ключ %s", dir, ring.ActiveKID())
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// LoginLockoutPolicy описывает ограничения на неудачные попытки входа
+type LoginLockoutPolicy struct {
+	// MaxAttempts — число неверных паролей подряд, после которого учетная запись блокируется
+	MaxAttempts int
+	// BaseDuration — длительность первой блокировки, каждая следующая вдвое дольше
+	BaseDuration time.Duration
+	// MaxDuration — верхняя граница длительности блокировки
+	MaxDuration time.Duration
+
+	// IPMaxAttempts — число неудачных входов с одного IP за IPWindow, после которого вход с него временно запрещен
+	IPMaxAttempts int
+	IPWindow      time.Duration
+}
+
+var LoginLockout LoginLockoutPolicy
+
+// initLoginLockout читает ограничения на неудачные попытки входа из переменных окружения
+func initLoginLockout() {
+	LoginLockout = LoginLockoutPolicy{
+		MaxAttempts:   getIntEnv("LOGIN_MAX_ATTEMPTS", 5),
+		BaseDuration:  getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		MaxDuration:   getDurationEnv("LOGIN_LOCKOUT_MAX_DURATION", 24*time.Hour),
+		IPMaxAttempts: getIntEnv("LOGIN_IP_MAX_ATTEMPTS", 20),
+		IPWindow:      getDurationEnv("LOGIN_IP_WINDOW", 15*time.Minute),
+	}
+}
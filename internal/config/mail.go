@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"userManagement/internal/mailer"
+	"userManagement/internal/utils"
+)
+
+var (
+	// Mailer отправляет письма пользователям (сброс пароля, подтверждение email)
+	Mailer mailer.Mailer
+
+	// AppBaseURL используется для построения ссылок в письмах
+	AppBaseURL string
+)
+
+// initMailer выбирает способ отправки писем по переменной MAIL_DRIVER
+func initMailer() {
+	AppBaseURL = getEnv("APP_BASE_URL", "http://localhost:8080")
+
+	switch driver := getEnv("MAIL_DRIVER", "log"); driver {
+	case "smtp":
+		Mailer = mailer.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			getEnv("SMTP_PORT", "587"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	case "file":
+		Mailer = mailer.NewFileMailer(getEnv("MAIL_FILE_PATH", "logs/mail.log"))
+	case "log":
+		Mailer = mailer.NewFileMailer("")
+	default:
+		utils.Log.Warnf("Неизвестный MAIL_DRIVER=%q, письма будут записываться в лог", driver)
+		Mailer = mailer.NewFileMailer("")
+	}
+}
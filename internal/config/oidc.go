@@ -0,0 +1,42 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	"userManagement/internal/utils"
+)
+
+var (
+	// OIDCEnabled — провайдер OpenID Connect включен. Он работает только с асимметричными ключами:
+	// при HS256 клиенту для проверки ID-токена понадобился бы секрет, которым подписываются и access-токены сервиса
+	OIDCEnabled bool
+
+	// OIDCIssuer — идентификатор провайдера OpenID Connect (claim iss), по нему клиенты находят discovery-документ
+	OIDCIssuer string
+
+	// AuthorizationCodeTTL — время, за которое клиент должен обменять код авторизации на токены
+	AuthorizationCodeTTL time.Duration
+
+	// OIDCLoginURL — страница входа и согласия во фронтенде. Браузер, пришедший на /oauth/authorize без токена,
+	// перенаправляется на нее с теми же параметрами запроса
+	OIDCLoginURL string
+)
+
+// initOIDC читает настройки провайдера OpenID Connect из переменных окружения
+func initOIDC() {
+	OIDCIssuer = strings.TrimSuffix(getEnv("OIDC_ISSUER", AppBaseURL), "/")
+	AuthorizationCodeTTL = getDurationEnv("OIDC_AUTH_CODE_TTL", time.Minute)
+
+	OIDCLoginURL = getEnv("OIDC_LOGIN_URL", "")
+	if OIDCLoginURL != "" {
+		if loginURL, err := url.Parse(OIDCLoginURL); err != nil || !loginURL.IsAbs() {
+			utils.Log.Fatalf("OIDC_LOGIN_URL должен быть абсолютным адресом: %s", OIDCLoginURL)
+		}
+	}
+
+	OIDCEnabled = Keys.Asymmetric()
+	if !OIDCEnabled {
+		utils.Log.Warn("Провайдер OpenID Connect отключен: для подписи ID-токенов задайте JWT_KEYS_DIR с ключами RS256 или EdDSA")
+	}
+}
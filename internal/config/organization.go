@@ -0,0 +1,44 @@
+package config
+
+import (
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultOrganizationID — ID организации по умолчанию, заполняется при инициализации БД
+var DefaultOrganizationID uint
+
+// migrateToOrganizations переносит пользователей, группы и роли, созданные до разделения на организации,
+// в организацию по умолчанию и удаляет прежние глобальные ограничения уникальности email и названий
+func migrateToOrganizations(db *gorm.DB, organizationID uint) error {
+	for _, model := range []interface{}{&models.User{}, &models.Group{}, &models.Role{}} {
+		if err := db.Unscoped().Model(model).
+			Where("organization_id IS NULL OR organization_id = 0").
+			Update("organization_id", organizationID).Error; err != nil {
+			return err
+		}
+	}
+
+	migrator := db.Migrator()
+	if migrator.HasIndex(&models.User{}, "idx_users_email") {
+		if err := migrator.DropIndex(&models.User{}, "idx_users_email"); err != nil {
+			return err
+		}
+	}
+	legacyConstraints := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.Group{}, "groups_name_key"},
+		{&models.Role{}, "roles_name_key"},
+	}
+	for _, legacy := range legacyConstraints {
+		if migrator.HasConstraint(legacy.model, legacy.name) {
+			if err := migrator.DropConstraint(legacy.model, legacy.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import "userManagement/internal/utils"
+
+// bcryptMaxPasswordBytes — bcrypt учитывает только первые 72 байта пароля
+const bcryptMaxPasswordBytes = 72
+
+// PasswordPolicy описывает требования к новым паролям
+type PasswordPolicy struct {
+	MinLength      int
+	MaxBytes       int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+
+	// ForbidPersonalData запрещает использовать в пароле email и имя пользователя
+	ForbidPersonalData bool
+
+	// BreachedFile — путь к файлу SHA-1 хэшей утекших паролей. Пустой путь отключает проверку.
+	BreachedFile string
+}
+
+var Password PasswordPolicy
+
+// initPasswordPolicy читает требования к паролям из переменных окружения
+func initPasswordPolicy() {
+	Password = PasswordPolicy{
+		MinLength:          getIntEnv("PASSWORD_MIN_LENGTH", 8),
+		MaxBytes:           getIntEnv("PASSWORD_MAX_BYTES", bcryptMaxPasswordBytes),
+		RequireUpper:       getBoolEnv("PASSWORD_REQUIRE_UPPER", false),
+		RequireLower:       getBoolEnv("PASSWORD_REQUIRE_LOWER", false),
+		RequireDigit:       getBoolEnv("PASSWORD_REQUIRE_DIGIT", false),
+		RequireSpecial:     getBoolEnv("PASSWORD_REQUIRE_SPECIAL", false),
+		ForbidPersonalData: getBoolEnv("PASSWORD_FORBID_PERSONAL_DATA", true),
+		BreachedFile:       getEnv("BREACHED_PASSWORDS_FILE", ""),
+	}
+
+	// Длинный пароль bcrypt молча обрежет, поэтому больше 72 байт не разрешаем
+	if Password.MaxBytes == 0 || Password.MaxBytes > bcryptMaxPasswordBytes {
+		utils.Log.Warnf("PASSWORD_MAX_BYTES=%d вне допустимого диапазона, используется %d", Password.MaxBytes, bcryptMaxPasswordBytes)
+		Password.MaxBytes = bcryptMaxPasswordBytes
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+)
+
+// ClaimMapping сопоставляет значение claim групп провайдера с ролью или группой сервиса
+type ClaimMapping struct {
+	Claim  string
+	Target string
+}
+
+// SSOProvider — настройки входа через внешнего провайдера OpenID Connect
+type SSOProvider struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// GroupsClaim — claim ID-токена со списком групп пользователя у провайдера
+	GroupsClaim string
+	// DefaultRole назначается новым пользователям, если ни одна группа не сопоставлена с ролью
+	DefaultRole string
+	// RoleMapping проверяется по порядку, пользователь получает роль первого совпадения
+	RoleMapping []ClaimMapping
+	// GroupMapping определяет членство в группах сервиса
+	GroupMapping []ClaimMapping
+	// LinkByEmail разрешает связать вход через провайдера с существующей учетной записью по подтвержденному email
+	LinkByEmail bool
+	// Organization — slug организации, в которой ищутся и создаются пользователи провайдера
+	Organization string
+}
+
+var (
+	// SSOProviders — настроенные провайдеры по имени, SSOProviderNames сохраняет порядок из SSO_PROVIDERS
+	SSOProviders     map[string]SSOProvider
+	SSOProviderNames []string
+
+	// SSOStateTTL — время, за которое нужно завершить вход на стороне провайдера
+	SSOStateTTL time.Duration
+)
+
+var ssoProviderName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// initSSO читает настройки провайдеров из переменных окружения.
+// Список провайдеров задается в SSO_PROVIDERS, параметры каждого — в SSO_<ИМЯ>_*.
+func initSSO() {
+	SSOProviders = map[string]SSOProvider{}
+	SSOProviderNames = nil
+	SSOStateTTL = getDurationEnv("SSO_STATE_TTL", 10*time.Minute)
+
+	for _, name := range strings.Split(getEnv("SSO_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !ssoProviderName.MatchString(name) {
+			utils.Log.Fatalf("Некорректное имя провайдера SSO %q: допустимы латинские буквы, цифры, _ и -", name)
+		}
+
+		prefix := "SSO_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := SSOProvider{
+			Name:         name,
+			DisplayName:  getEnv(prefix+"DISPLAY_NAME", name),
+			Issuer:       strings.TrimSuffix(getEnv(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimSuffix(AppBaseURL, "/")+"/auth/sso/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid profile email")),
+			GroupsClaim:  getEnv(prefix+"GROUPS_CLAIM", "groups"),
+			DefaultRole:  getEnv(prefix+"DEFAULT_ROLE", models.RoleUser),
+			RoleMapping:  parseClaimMappings(prefix+"ROLE_MAP", getEnv(prefix+"ROLE_MAP", "")),
+			GroupMapping: parseClaimMappings(prefix+"GROUP_MAP", getEnv(prefix+"GROUP_MAP", "")),
+			LinkByEmail:  getBoolEnv(prefix+"LINK_BY_EMAIL", true),
+			Organization: getEnv(prefix+"ORGANIZATION", models.DefaultOrganizationSlug),
+		}
+
+		if provider.Issuer == "" || provider.ClientID == "" {
+			utils.Log.Fatalf("Для провайдера SSO %s нужно задать %sISSUER и %sCLIENT_ID", name, prefix, prefix)
+		}
+
+		if _, exists := SSOProviders[name]; !exists {
+			SSOProviderNames = append(SSOProviderNames, name)
+		}
+		SSOProviders[name] = provider
+		utils.Log.Infof("Настроен вход через провайдера SSO %s (%s)", name, provider.Issuer)
+	}
+}
+
+// parseClaimMappings разбирает список вида "группа-провайдера=цель,другая=цель"
+func parseClaimMappings(key, value string) []ClaimMapping {
+	var mappings []ClaimMapping
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		claim, target, ok := strings.Cut(pair, "=")
+		claim, target = strings.TrimSpace(claim), strings.TrimSpace(target)
+		if !ok || claim == "" || target == "" {
+			utils.Log.Warnf("Некорректный элемент %q в %s, ожидается значение=цель", pair, key)
+			continue
+		}
+		mappings = append(mappings, ClaimMapping{Claim: claim, Target: target})
+	}
+	return mappings
+}
@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+var (
+	// TOTPIssuer отображается в приложении-аутентификаторе рядом с учетной записью
+	TOTPIssuer string
+
+	// TwoFactorChallengeTTL — время, за которое нужно ввести код после ввода пароля
+	TwoFactorChallengeTTL time.Duration
+)
+
+// initTwoFactor читает настройки двухфакторной аутентификации из переменных окружения
+func initTwoFactor() {
+	TOTPIssuer = getEnv("TOTP_ISSUER", "UserManagement")
+	TwoFactorChallengeTTL = getDurationEnv("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+}
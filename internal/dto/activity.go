@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// ActivityLogQuery — параметры фильтрации и пагинации журнала активности
+type ActivityLogQuery struct {
+	PaginationQuery
+	UserID     uint       `form:"user_id"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"target_type" binding:"omitempty,oneof=user role group oauth_client"`
+	TargetID   uint       `form:"target_id"`
+	From       *time.Time `form:"from"`
+	To         *time.Time `form:"to"`
+}
+
+// ActivityChainReport — результат проверки цепочки хэшей журнала активности
+type ActivityChainReport struct {
+	Valid      bool   `json:"valid"`
+	Checked    int    `json:"checked"`
+	Legacy     int    `json:"legacy"`
+	BrokenAtID *uint  `json:"broken_at_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	HeadHash   string `json:"head_hash"`
+}
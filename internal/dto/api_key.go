@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+)
+
+// CreateAPIKeyInput используется для выпуска персонального API-ключа.
+// Scopes — коды прав из числа прав роли пользователя, например users:read.
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (i *CreateAPIKeyInput) Sanitize() {
+	i.Name = utils.SanitizeInput(i.Name)
+}
+
+// APIKeyCreatedResponse содержит выпущенный ключ. Значение key показывается один раз.
+type APIKeyCreatedResponse struct {
+	APIKey models.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
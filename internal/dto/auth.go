@@ -1,14 +1,53 @@
 package dto
 
-// RegisterInput используется для регистрации нового пользователя
+// RegisterInput используется для регистрации нового пользователя.
+// Требования к паролю задаются политикой паролей и проверяются в services.ValidatePassword.
 type RegisterInput struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
+
+	// Organization — slug организации, если не указан, используется организация по умолчанию
+	Organization string `json:"organization" binding:"max=64"`
 }
 
-// LoginInput используется для входа пользователя
+// LoginInput используется для входа пользователя. Email уникален только в пределах организации.
 type LoginInput struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Email        string `json:"email" binding:"required,email"`
+	Password     string `json:"password" binding:"required"`
+	Organization string `json:"organization" binding:"max=64"`
+}
+
+// RefreshInput используется для обновления пары токенов
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput используется для выхода из системы.
+// Если передан refresh-токен, он отзывается вместе со всем своим семейством.
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordInput используется для запроса ссылки на сброс пароля
+type ForgotPasswordInput struct {
+	Email        string `json:"email" binding:"required,email"`
+	Organization string `json:"organization" binding:"max=64"`
+}
+
+// ResetPasswordInput используется для установки нового пароля по ссылке из письма
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailQuery используется для подтверждения email по ссылке из письма
+type VerifyEmailQuery struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// ResendVerificationInput используется для повторной отправки письма с подтверждением email
+type ResendVerificationInput struct {
+	Email        string `json:"email" binding:"required,email"`
+	Organization string `json:"organization" binding:"max=64"`
 }
@@ -1,17 +1,79 @@
 package dto
 
-import "userManagement/internal/utils"
+import (
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+)
 
-// GroupInput используется для создания или обновления группы
+// GroupInput используется для создания или обновления группы.
+// Пустой parent_id делает группу корневой, metadata заменяется целиком
 type GroupInput struct {
-	Name string `json:"name" binding:"required"`
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description" binding:"max=1000"`
+	Metadata    map[string]string `json:"metadata" binding:"omitempty,max=50,dive,keys,min=1,max=64,endkeys,max=1000"`
+	ParentID    *uint             `json:"parent_id"`
 }
 
 func (i *GroupInput) Sanitize() {
 	i.Name = utils.SanitizeInput(i.Name)
+	i.Description = utils.SanitizeInput(i.Description)
+	if len(i.Metadata) == 0 {
+		i.Metadata = nil
+	}
 }
 
-// UserGroupInput используется для добавления пользователя в группу
+// UserGroupInput используется для добавления пользователя в группу.
+// Без роли пользователь добавляется обычным участником (member)
 type UserGroupInput struct {
-	UserID uint `json:"user_id" binding:"required"`
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"omitempty,oneof=owner manager member"`
+}
+
+// GroupMemberRoleInput используется для изменения роли участника группы
+type GroupMemberRoleInput struct {
+	Role string `json:"role" binding:"required,oneof=owner manager member"`
+}
+
+// GroupMembersInput используется для добавления и исключения нескольких участников одним запросом
+type GroupMembersInput struct {
+	Add    []UserGroupInput `json:"add" binding:"max=100,dive"`
+	Remove []uint           `json:"remove" binding:"max=100,dive,min=1"`
+}
+
+// GroupMembersResult - участники, которые действительно были добавлены или исключены
+type GroupMembersResult struct {
+	Added   []uint `json:"added"`
+	Removed []uint `json:"removed"`
+}
+
+// GroupMembersQuery - параметры постраничного списка участников группы
+type GroupMembersQuery struct {
+	PaginationQuery
+	Role   string `form:"role" binding:"omitempty,oneof=owner manager member"`
+	Search string `form:"search"`
+}
+
+// GroupDetails - группа с количеством прямых участников и списком подгрупп
+type GroupDetails struct {
+	models.Group
+	MemberCount int64          `json:"member_count"`
+	Subgroups   []models.Group `json:"subgroups"`
+}
+
+// UserGroupInfo - группа, в которой состоит пользователь, вместе с его ролью в ней
+type UserGroupInfo struct {
+	models.Group
+	GroupRole string `json:"group_role"`
+}
+
+// GroupMemberInfo - участник группы вместе с его ролью в ней
+type GroupMemberInfo struct {
+	models.User
+	GroupRole string `json:"group_role"`
+}
+
+// GroupWithMembers - группа со списком участников и их ролями
+type GroupWithMembers struct {
+	models.Group
+	Users []GroupMemberInfo `json:"users"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+// ImpersonateInput используется для входа от имени пользователя. Причина сохраняется в журнале активности.
+type ImpersonateInput struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ImpersonationResponse содержит токен входа от имени пользователя. Refresh-токен не выдается:
+// по истечении срока нужно запросить новый токен.
+type ImpersonationResponse struct {
+	Token     string `json:"token"`
+	ExpiresIn int64  `json:"expires_in"`
+	UserID    uint   `json:"user_id"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+)
+
+// AuthorizeRequest — параметры запроса авторизации OpenID Connect
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	Nonce               string `form:"nonce" json:"nonce"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// ConsentInput — ответ пользователя на запрос согласия стороннего клиента
+type ConsentInput struct {
+	AuthorizeRequest
+	Approve bool `form:"approve" json:"approve"`
+}
+
+// ConsentPrompt возвращается, если сторонний клиент запрашивает доступ впервые.
+// Для выдачи кода нужно повторить запрос методом POST с approve=true.
+// Если согласие не требуется, возвращается AuthorizeRedirect.
+type ConsentPrompt struct {
+	ClientID   string   `json:"client_id"`
+	ClientName string   `json:"client_name"`
+	Scopes     []string `json:"scopes"`
+	Message    string   `json:"message"`
+}
+
+// AuthorizeRedirect — адрес возврата клиенту с кодом авторизации или ошибкой.
+// Запрос авторизации выполняет страница входа с access-токеном пользователя, поэтому адрес возвращается в теле
+// ответа, а перенаправляет браузер уже сама страница: ответ 302 на чужой домен из fetch прочитать нельзя.
+type AuthorizeRedirect struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// OAuthTokenResponse — ответ token endpoint
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// OAuthError — ошибка в формате RFC 6749
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OpenIDConfiguration — discovery-документ провайдера
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// OAuthClientInput используется для регистрации клиента OpenID Connect
+type OAuthClientInput struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,dive,url"`
+	Public       bool     `json:"public"`
+	FirstParty   bool     `json:"first_party"`
+}
+
+func (i *OAuthClientInput) Sanitize() {
+	i.Name = utils.SanitizeInput(i.Name)
+}
+
+// OAuthClientCreatedResponse содержит зарегистрированного клиента и его секрет, который показывается один раз
+type OAuthClientCreatedResponse struct {
+	Client       models.OAuthClient `json:"client"`
+	ClientSecret string             `json:"client_secret,omitempty"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+)
+
+// CreateOrganizationInput используется для создания организации вместе с ее первым администратором
+type CreateOrganizationInput struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	Slug          string `json:"slug" binding:"required,min=2,max=64"`
+	AdminName     string `json:"admin_name" binding:"required"`
+	AdminEmail    string `json:"admin_email" binding:"required,email"`
+	AdminPassword string `json:"admin_password" binding:"required"`
+	// RegistrationOpen разрешает самостоятельную регистрацию в организации, по умолчанию она закрыта
+	RegistrationOpen bool `json:"registration_open"`
+}
+
+func (i *CreateOrganizationInput) Sanitize() {
+	i.Name = utils.SanitizeInput(i.Name)
+	i.AdminName = utils.SanitizeInput(i.AdminName)
+	i.AdminEmail = utils.SanitizeInput(i.AdminEmail)
+}
+
+// UpdateOrganizationInput используется для изменения настроек организации
+type UpdateOrganizationInput struct {
+	RegistrationOpen *bool `json:"registration_open" binding:"required"`
+}
+
+// OrganizationCreatedResponse содержит созданную организацию и ее администратора
+type OrganizationCreatedResponse struct {
+	Organization models.Organization `json:"organization"`
+	Admin        models.User         `json:"admin"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PaginationQuery — параметры постраничной выборки
+type PaginationQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// Normalize подставляет значения по умолчанию для незаданных параметров
+func (q *PaginationQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize > MaxPageSize {
+		q.PageSize = MaxPageSize
+	}
+}
+
+// Offset возвращает количество пропускаемых записей
+func (q PaginationQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// PageMeta — метаданные страницы
+type PageMeta struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	NextPage   *int  `json:"next_page"`
+}
+
+// PageResponse — единый формат ответа для постраничных списков
+type PageResponse struct {
+	Data interface{} `json:"data"`
+	Meta PageMeta    `json:"meta"`
+}
+
+// NewPageResponse формирует ответ со списком и метаданными страницы
+func NewPageResponse(data interface{}, q PaginationQuery, total int64) PageResponse {
+	totalPages := int((total + int64(q.PageSize) - 1) / int64(q.PageSize))
+
+	meta := PageMeta{
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+	if q.Page < totalPages {
+		next := q.Page + 1
+		meta.NextPage = &next
+	}
+
+	return PageResponse{Data: data, Meta: meta}
+}
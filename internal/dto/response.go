@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // ResponseError - структура для ответа с ошибкой
 type ResponseError struct {
 	Message string `json:"message"`
@@ -12,5 +14,23 @@ type ResponseMessage struct {
 
 // AuthResponse структура для ответа при успешной аутентификации
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+
+	// TwoFactorSetupRequired означает, что роль требует 2FA и до ее настройки доступны только эндпоинты /users/me/2fa
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+// LockedResponse - ответ для учетной записи, временно заблокированной после неудачных попыток входа
+type LockedResponse struct {
+	Message     string    `json:"message"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// BannedResponse - ответ для заблокированного пользователя
+type BannedResponse struct {
+	Message     string     `json:"message"`
+	Reason      string     `json:"reason,omitempty"`
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
 }
@@ -0,0 +1,34 @@
+package dto
+
+import "userManagement/internal/utils"
+
+// RoleInput используется для создания роли
+type RoleInput struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Require2FA  bool     `json:"require_2fa"`
+	Permissions []string `json:"permissions"`
+}
+
+func (i *RoleInput) Sanitize() {
+	i.Name = utils.SanitizeInput(i.Name)
+	i.Description = utils.SanitizeInput(i.Description)
+}
+
+// RolePermissionsInput используется для замены набора прав роли
+type RolePermissionsInput struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateRoleInput используется для переименования роли, изменения ее описания
+// и требования двухфакторной аутентификации. Если require_2fa не передан, требование не меняется.
+type UpdateRoleInput struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Require2FA  *bool  `json:"require_2fa"`
+}
+
+func (i *UpdateRoleInput) Sanitize() {
+	i.Name = utils.SanitizeInput(i.Name)
+	i.Description = utils.SanitizeInput(i.Description)
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "userManagement/internal/models"
+
+// SessionInfo — сессия пользователя в списке сессий. Current отмечает сессию, из которой выполнен запрос.
+type SessionInfo struct {
+	models.Session
+	Current bool `json:"current"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// SSOProviderInfo описывает провайдера, через которого можно войти
+type SSOProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+// SSOCallbackQuery — параметры возврата с провайдера
+type SSOCallbackQuery struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
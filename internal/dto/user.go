@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"time"
 	"userManagement/internal/utils"
 )
 
@@ -8,7 +9,7 @@ import (
 type CreateUserInput struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 func (i *CreateUserInput) Sanitize() {
@@ -32,3 +33,33 @@ func (i *UpdateUserInput) Sanitize() {
 type UpdateUserRoleInput struct {
 	RoleName string `json:"role_name" binding:"required"`
 }
+
+// BanUserInput используется для блокировки пользователя.
+// Если Until не указан, блокировка бессрочная.
+type BanUserInput struct {
+	Reason string     `json:"reason" binding:"max=255"`
+	Until  *time.Time `json:"until"`
+}
+
+func (i *BanUserInput) Sanitize() {
+	i.Reason = utils.SanitizeInput(i.Reason)
+}
+
+// UserListQuery — параметры фильтрации, сортировки и пагинации списка пользователей
+type UserListQuery struct {
+	PaginationQuery
+	Role        string     `form:"role"`
+	Search      string     `form:"search"`
+	IsBanned    *bool      `form:"is_banned"`
+	GroupID     uint       `form:"group_id"`
+	CreatedFrom *time.Time `form:"created_from"`
+	CreatedTo   *time.Time `form:"created_to"`
+	SortBy      string     `form:"sort_by" binding:"omitempty,oneof=name email created_at"`
+	Order       string     `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// ChangePasswordInput используется для смены пароля текущим пользователем
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
@@ -4,7 +4,36 @@ import "userManagement/internal/models"
 
 // UserInfo используется для передачи информации о пользователе
 type UserInfo struct {
-	ID     uint         `json:"id"`
-	RoleID uint         `json:"role_id"`
-	Role   *models.Role `json:"role"`
+	ID             uint         `json:"id"`
+	OrganizationID uint         `json:"organization_id"`
+	RoleID         uint         `json:"role_id"`
+	Role           *models.Role `json:"role"`
+
+	// EmailVerified нужен для ограничения прав при политике limited
+	EmailVerified bool `json:"email_verified"`
+
+	// APIKeyID и APIKeyScopes заполняются, если запрос авторизован API-ключом, а не токеном
+	APIKeyID     uint     `json:"api_key_id,omitempty"`
+	APIKeyScopes []string `json:"api_key_scopes,omitempty"`
+
+	// ImpersonatorID заполняется, если сотрудник вошел от имени пользователя: права проверяются по роли пользователя,
+	// а действия записываются в журнал от имени сотрудника
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
+}
+
+// HasPermission проверяет право роли пользователя. Для запросов с API-ключом право
+// должно также входить в области ключа.
+func (u UserInfo) HasPermission(permission string) bool {
+	if !u.Role.HasPermission(permission) {
+		return false
+	}
+	if u.APIKeyID == 0 {
+		return true
+	}
+	for _, scope := range u.APIKeyScopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
 }
@@ -2,32 +2,162 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"userManagement/internal/config"
 	"userManagement/internal/dto"
 	"userManagement/internal/models"
+	"userManagement/internal/services"
 	"userManagement/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetActivityLogs godoc
 // @Summary      Получить логи активности
+// @Description  Постраничный журнал активности с фильтрами по автору, коду действия, объекту и периоду.
 // @Tags         Activity
 // @Security     BearerAuth
 // @Produce      json
-// @Success      200 {array} models.ActivityLog
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        page_size query int false "Размер страницы (не более 100)" default(20)
+// @Param        user_id query int false "ID пользователя, совершившего действие"
+// @Param        action query string false "Код действия, например user.role_changed"
+// @Param        target_type query string false "Тип объекта действия" Enums(user, role, group, oauth_client)
+// @Param        target_id query int false "ID объекта действия"
+// @Param        from query string false "Начало периода (RFC3339)"
+// @Param        to query string false "Конец периода (RFC3339)"
+// @Success      200 {object} dto.PageResponse{data=[]models.ActivityLog}
+// @Failure      400 {object} dto.ResponseError
 // @Failure      401 {object} dto.ResponseError
 // @Router       /users/activity [get]
 func GetActivityLogs(c *gin.Context) {
+	var params dto.ActivityLogQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Log.Warnf("Некорректные параметры журнала активности: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	respondActivityLogs(c, activityLogQuery(c, params), params.PaginationQuery)
+}
+
+// GetUserActivity godoc
+// @Summary      Получить логи активности пользователя
+// @Description  Постраничный журнал действий, совершенных пользователем или над ним.
+// @Tags         Activity
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        page_size query int false "Размер страницы (не более 100)" default(20)
+// @Param        action query string false "Код действия, например user.role_changed"
+// @Param        from query string false "Начало периода (RFC3339)"
+// @Param        to query string false "Конец периода (RFC3339)"
+// @Success      200 {object} dto.PageResponse{data=[]models.ActivityLog}
+// @Failure      400 {object} dto.ResponseError
+// @Failure      401 {object} dto.ResponseError
+// @Router       /users/{id}/activity [get]
+func GetUserActivity(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Log.Warnf("Некорректный ID пользователя %s при запросе активности", c.Param("id"))
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Некорректный ID пользователя"})
+		return
+	}
+
+	var params dto.ActivityLogQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Log.Warnf("Некорректные параметры журнала активности: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+	params.UserID = 0
+	params.TargetType = ""
+	params.TargetID = 0
+
+	query := activityLogQuery(c, params).
+		Where("user_id = ? OR (target_type = ? AND target_id = ?)", userID, models.TargetUser, userID)
+
+	respondActivityLogs(c, query, params.PaginationQuery)
+}
+
+// respondActivityLogs выбирает страницу журнала активности и отправляет ее клиенту
+func respondActivityLogs(c *gin.Context, query *gorm.DB, pagination dto.PaginationQuery) {
+	pagination.Normalize()
+
 	var logs []models.ActivityLog
 
-	// Получаем логи из базы данных
-	if err := config.DB.Order("timestamp desc").Find(&logs).Error; err != nil {
+	page, total, err := paginate(query, pagination)
+	if err == nil {
+		err = page.Order("timestamp desc").Order("id desc").Find(&logs).Error
+	}
+	if err != nil {
 		utils.Log.Errorf("Ошибка получения логов активности: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить логи"})
 		return
 	}
 
 	utils.Log.Info("Логи активности успешно получены")
-	c.JSON(http.StatusOK, logs)
+	c.JSON(http.StatusOK, dto.NewPageResponse(logs, pagination, total))
+}
+
+// activityLogQuery строит запрос к журналу активности организации с учетом фильтров.
+// Записи, сделанные до разделения на организации, видны только в организации по умолчанию.
+func activityLogQuery(c *gin.Context, params dto.ActivityLogQuery) *gorm.DB {
+	organizationID := c.GetUint("organizationID")
+	query := config.DB.Model(&models.ActivityLog{})
+	if organizationID == config.DefaultOrganizationID {
+		query = query.Where("organization_id = ? OR organization_id IS NULL", organizationID)
+	} else {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+
+	if params.UserID != 0 {
+		query = query.Where("user_id = ?", params.UserID)
+	}
+	if action := strings.TrimSpace(params.Action); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if params.TargetType != "" {
+		query = query.Where("target_type = ?", params.TargetType)
+	}
+	if params.TargetID != 0 {
+		query = query.Where("target_id = ?", params.TargetID)
+	}
+	if params.From != nil {
+		query = query.Where("timestamp >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("timestamp <= ?", *params.To)
+	}
+
+	return query
+}
+
+// VerifyActivityChain godoc
+// @Summary      Проверить целостность журнала активности
+// @Description  Проходит цепочку хэшей журнала и сообщает о первой измененной, удаленной или вставленной записи.
+// @Tags         Activity
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200 {object} dto.ActivityChainReport
+// @Failure      401 {object} dto.ResponseError
+// @Failure      500 {object} dto.ResponseError
+// @Router       /users/activity/verify [get]
+func VerifyActivityChain(c *gin.Context) {
+	report, err := services.VerifyActivityChain()
+	if err != nil {
+		utils.Log.Errorf("Ошибка проверки цепочки журнала активности: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось проверить журнал активности"})
+		return
+	}
+
+	if !report.Valid {
+		utils.Log.Warnf("Цепочка журнала активности нарушена на записи ID=%d: %s", *report.BrokenAtID, report.Reason)
+	} else {
+		utils.Log.Infof("Цепочка журнала активности цела, проверено записей: %d", report.Checked)
+	}
+	c.JSON(http.StatusOK, report)
 }
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetAPIKeys godoc
+// @Summary Список API-ключей текущего пользователя
+// @Description Возвращает ключи без секретной части: префикс, области, срок действия и время последнего использования.
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Запрос выполнен по API-ключу"
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении ключей"
+// @Router /users/me/api-keys [get]
+func GetAPIKeys(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	keys, err := services.ListAPIKeys(userID)
+	if err != nil {
+		utils.Log.Errorf("Не удалось получить API-ключи пользователя ID=%d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить API-ключи"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Выпуск API-ключа
+// @Description Создает персональный ключ для скриптов и CI. Ключ передается в заголовке Authorization: ApiKey <key>
+// @Description и действует от имени пользователя в пределах указанных прав. Значение ключа показывается один раз.
+// @Tags API Keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateAPIKeyInput true "Название, права и срок действия ключа"
+// @Success 201 {object} dto.APIKeyCreatedResponse
+// @Failure 400 {object} dto.ResponseError "Неверный ввод, право не входит в роль или достигнут лимит ключей"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Запрос выполнен по API-ключу"
+// @Failure 500 {object} dto.ResponseError "Ошибка при создании ключа"
+// @Router /users/me/api-keys [post]
+func CreateAPIKey(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при создании API-ключа: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	key, raw, err := services.CreateAPIKey(userID, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAPIKeyScope), errors.Is(err, services.ErrAPIKeyLimit), errors.Is(err, services.ErrAPIKeyExpiresAt):
+			utils.Log.Warnf("Отказ в создании API-ключа пользователю ID=%d: %v", userID, err)
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		default:
+			utils.Log.Errorf("Не удалось создать API-ключ пользователю ID=%d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось создать API-ключ"})
+		}
+		return
+	}
+
+	logAudit(c, models.ActionUserAPIKeyCreated, models.TargetUser, userID, models.AuditChanges{}.
+		Add("api_key_id", nil, key.ID).
+		Add("name", nil, key.Name).
+		Add("prefix", nil, key.Prefix).
+		Add("scopes", nil, key.Scopes).
+		Add("expires_at", nil, key.ExpiresAt))
+
+	utils.Log.Infof("Пользователь ID=%d создал API-ключ %s", userID, key.Prefix)
+	c.JSON(http.StatusCreated, dto.APIKeyCreatedResponse{APIKey: key, Key: raw})
+}
+
+// DeleteAPIKey godoc
+// @Summary Отзыв API-ключа
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce json
+// @Param keyId path int true "ID ключа"
+// @Success 200 {object} dto.ResponseMessage "Ключ отозван"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Запрос выполнен по API-ключу"
+// @Failure 404 {object} dto.ResponseError "Ключ не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при отзыве ключа"
+// @Router /users/me/api-keys/{keyId} [delete]
+func DeleteAPIKey(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Ключ не найден"})
+		return
+	}
+
+	key, err := services.DeleteAPIKey(userID, uint(keyID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Ключ не найден"})
+			return
+		}
+		utils.Log.Errorf("Не удалось отозвать API-ключ ID=%d пользователя ID=%d: %v", keyID, userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось отозвать API-ключ"})
+		return
+	}
+
+	logAudit(c, models.ActionUserAPIKeyRevoked, models.TargetUser, userID, models.AuditChanges{}.
+		Add("api_key_id", key.ID, nil).
+		Add("name", key.Name, nil).
+		Add("prefix", key.Prefix, nil))
+
+	utils.Log.Infof("Пользователь ID=%d отозвал API-ключ %s", userID, key.Prefix)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "API-ключ отозван"})
+}
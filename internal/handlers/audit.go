@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logAudit записывает событие в журнал активности от имени текущего пользователя,
+// дополняя его IP-адресом и User-Agent запроса. При входе от имени пользователя автором события
+// записывается сотрудник, а пользователь — в поле impersonated_user_id.
+func logAudit(c *gin.Context, action models.ActionCode, targetType string, targetID uint, changes models.AuditChanges) {
+	userID := c.GetUint("userID")
+	if realUserID := c.GetUint("realUserID"); realUserID != 0 && realUserID != userID {
+		if changes == nil {
+			changes = models.AuditChanges{}
+		}
+		logAuditAs(c, realUserID, action, targetType, targetID, changes.Add("impersonated_user_id", nil, userID))
+		return
+	}
+	logAuditAs(c, userID, action, targetType, targetID, changes)
+}
+
+// logAuditAs записывает событие от имени указанного пользователя.
+// Используется в запросах без токена, например при сбросе пароля: организацию в таких запросах
+// нужно предварительно сохранить в контексте через setOrganization.
+func logAuditAs(c *gin.Context, actorID uint, action models.ActionCode, targetType string, targetID uint, changes models.AuditChanges) {
+	err := services.LogAction(services.AuditEvent{
+		OrganizationID: c.GetUint("organizationID"),
+		ActorID:        actorID,
+		Action:         action,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Changes:        changes,
+		IP:             c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+	})
+	if err != nil {
+		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
+	}
+}
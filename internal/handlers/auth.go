@@ -1,26 +1,31 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"time"
 	"userManagement/internal/config"
 	"userManagement/internal/dto"
 	"userManagement/internal/models"
+	"userManagement/internal/services"
 	"userManagement/internal/utils"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // Register godoc
 // @Summary Регистрация нового пользователя
+// @Description Создает пользователя и отправляет на email ссылку для подтверждения адреса.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param user body dto.RegisterInput true "Регистрационные данные"
 // @Success 201 {object} dto.ResponseMessage "Регистрация прошла успешно"
-// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных или пароль не соответствует требованиям"
+// @Failure 403 {object} dto.ResponseError "Регистрация в организации закрыта"
 // @Failure 500 {object} dto.ResponseError "Ошибка при хешировании пароля или сохранении данных"
 // @Router /auth/register [post]
 func Register(c *gin.Context) {
@@ -31,6 +36,28 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	organization, err := services.ResolveOrganization(input.Organization)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOrganization) {
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Организация не найдена"})
+			return
+		}
+		utils.Log.Errorf("Ошибка при поиске организации %s: %v", input.Organization, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось зарегистрироваться"})
+		return
+	}
+	if !organization.RegistrationOpen {
+		utils.Log.Warnf("Попытка регистрации в организации %s с закрытой регистрацией", organization.Slug)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Регистрация в организации закрыта"})
+		return
+	}
+
+	if err := services.ValidatePassword(input.Password, input.Email, input.Name); err != nil {
+		utils.Log.Warnf("Пароль не соответствует политике при регистрации %s: %v", input.Email, err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
 	hashedPassword, errPassword := utils.HashPassword(input.Password)
 	if errPassword != nil {
 		utils.Log.Errorf("Ошибка хеширования пароля: %v", errPassword)
@@ -38,11 +65,20 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// Новым пользователям назначается роль обычного пользователя
+	role, err := services.GetDefaultRole(organization.ID)
+	if err != nil {
+		utils.Log.Errorf("Не удалось получить роль по умолчанию: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось зарегистрироваться"})
+		return
+	}
+
 	user := models.User{
-		Name:         input.Name,
-		Email:        input.Email,
-		PasswordHash: hashedPassword,
-		RoleID:       3, // обычный пользователь
+		OrganizationID: organization.ID,
+		Name:           input.Name,
+		Email:          input.Email,
+		PasswordHash:   hashedPassword,
+		RoleID:         role.ID,
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
@@ -51,19 +87,29 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	utils.Log.Info("Пользователь %s (%s) зарегистрирован", user.Name, user.Email)
-	c.JSON(http.StatusCreated, dto.ResponseMessage{Message: "Регистрация прошла успешно"})
+	// Ошибка отправки не отменяет регистрацию: письмо можно запросить повторно
+	if err := services.SendVerificationEmail(user); err != nil {
+		utils.Log.Errorf("Не удалось отправить письмо для подтверждения email %s: %v", user.Email, err)
+	}
+
+	utils.Log.Infof("Пользователь %s (%s) зарегистрирован", user.Name, user.Email)
+	c.JSON(http.StatusCreated, dto.ResponseMessage{Message: "Регистрация прошла успешно, подтвердите email по ссылке из письма"})
 }
 
 // Login godoc
 // @Summary Вход пользователя в систему
+// @Description Если у пользователя включена двухфакторная аутентификация, вместо токенов возвращается
+// @Description dto.TwoFactorChallengeResponse: токен второго шага нужно передать в /auth/2fa/verify вместе с кодом.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param login body dto.LoginInput true "Данные для входа"
-// @Success 200 {object} dto.AuthResponse "JWT токен"
+// @Success 200 {object} dto.AuthResponse "Access- и refresh-токены либо dto.TwoFactorChallengeResponse"
 // @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
 // @Failure 401 {object} dto.ResponseError "Неверный email или пароль"
+// @Failure 403 {object} dto.BannedResponse "Учетная запись заблокирована или email не подтвержден"
+// @Failure 423 {object} dto.LockedResponse "Учетная запись временно заблокирована после неудачных попыток входа"
+// @Failure 429 {object} dto.ResponseError "Слишком много неудачных попыток входа с IP"
 // @Router /auth/login [post]
 func Login(c *gin.Context) {
 	var input dto.LoginInput
@@ -73,33 +119,402 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	ip := c.ClientIP()
+	if services.IsIPLoginBlocked(ip) {
+		utils.Log.Warnf("Вход с IP %s временно запрещен из-за неудачных попыток", ip)
+		c.JSON(http.StatusTooManyRequests, dto.ResponseError{Message: "Слишком много неудачных попыток входа. Попробуйте позже"})
+		return
+	}
+
+	// Неизвестная организация не раскрывается: ответ такой же, как для неверного email
 	var user models.User
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	organization, err := services.ResolveOrganization(input.Organization)
+	if err == nil {
+		err = config.DB.Preload("Role").
+			Where("organization_id = ? AND email = ?", organization.ID, input.Email).
+			First(&user).Error
+	}
+	if err != nil {
 		utils.Log.Warnf("Попытка входа с неверным email: %s", input.Email)
-		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Неверный email или пароль"})
+		if _, err := services.RegisterFailedLogin(nil, ip); err != nil {
+			utils.Log.Errorf("Ошибка при учете неудачного входа: %v", err)
+		}
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Неверный email или пароль"})
+		return
+	}
+
+	setOrganization(c, user.OrganizationID)
+
+	// Пароль заблокированной учетной записи не проверяем, чтобы перебор не продолжался во время блокировки
+	if user.IsLocked(time.Now()) {
+		utils.Log.Warnf("Попытка входа в заблокированную после неудачных попыток учетную запись: %s", user.Email)
+		c.JSON(http.StatusLocked, dto.LockedResponse{
+			Message:     "Учетная запись временно заблокирована из-за неудачных попыток входа",
+			LockedUntil: *user.LockedUntil,
+		})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
 		utils.Log.Warnf("Неверный пароль для пользователя: %s", user.Email)
-		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Неверный email или пароль"})
+
+		lockedUntil, err := services.RegisterFailedLogin(&user, ip)
+		if err != nil {
+			utils.Log.Errorf("Ошибка при учете неудачного входа пользователя %s: %v", user.Email, err)
+		}
+		if lockedUntil != nil {
+			// Действие совершено неаутентифицированным клиентом, поэтому автор записи не указывается
+			logAuditAs(c, 0, models.ActionAuthAccountLocked, models.TargetUser, user.ID, models.AuditChanges{}.
+				Add("locked_until", nil, lockedUntil).
+				Add("lockout_count", user.LockoutCount-1, user.LockoutCount))
+
+			utils.Log.Warnf("Учетная запись %s заблокирована до %s после неудачных попыток входа", user.Email, lockedUntil.Format(time.RFC3339))
+			c.JSON(http.StatusLocked, dto.LockedResponse{
+				Message:     "Учетная запись временно заблокирована из-за неудачных попыток входа",
+				LockedUntil: *lockedUntil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Неверный email или пароль"})
 		return
 	}
 
-	// Создаем JWT токен
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"userID": user.ID,
-		"role":   user.Role,
-		"exp":    time.Now().Add(time.Hour * 72).Unix(),
-	})
+	completeLogin(c, user)
+}
 
-	tokenString, err := token.SignedString(config.JWTSecret)
+// completeLogin завершает вход после проверки учетных данных: проверяет блокировку и подтверждение email,
+// при включенной 2FA выдает токен второго шага, иначе — пару токенов.
+// Счетчик неудачных входов сбрасывается только при выдаче токенов: при 2FA — после проверки кода.
+func completeLogin(c *gin.Context, user models.User) {
+	// Заблокированным пользователям вход запрещен
+	if err := services.CheckBan(config.DB, &user); err != nil {
+		if errors.Is(err, services.ErrUserBanned) {
+			utils.Log.Warnf("Попытка входа заблокированного пользователя: %s", user.Email)
+			c.JSON(http.StatusForbidden, dto.BannedResponse{
+				Message:     "Учетная запись заблокирована",
+				Reason:      user.BanReason,
+				BannedUntil: user.BannedUntil,
+			})
+			return
+		}
+		utils.Log.Errorf("Ошибка при проверке блокировки пользователя %s: %v", user.Email, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
+		return
+	}
+
+	// При политике block_login вход возможен только после подтверждения email
+	if err := services.CheckEmailVerified(&user); err != nil {
+		utils.Log.Warnf("Попытка входа с неподтвержденным email: %s", user.Email)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Email не подтвержден, перейдите по ссылке из письма"})
+		return
+	}
+
+	// При включенной 2FA токены выдаются только после ввода кода на /auth/2fa/verify
+	if user.TwoFactorEnabled {
+		challenge, expiresIn, err := services.CreateTwoFactorChallenge(user)
+		if err != nil {
+			utils.Log.Errorf("Ошибка при создании токена второго шага для %s: %v", user.Email, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
+			return
+		}
+
+		utils.Log.Infof("Пользователь %s прошел первый шаг входа, ожидается код 2FA", user.Email)
+		c.JSON(http.StatusOK, dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         expiresIn,
+		})
+		return
+	}
+
+	// Создаем короткоживущий access-токен и refresh-токен нового семейства
+	tokens, err := services.IssueTokenPair(user, clientInfo(c))
 	if err != nil {
 		utils.Log.Errorf("Ошибка при создании токена для %s: %v", user.Email, err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
 		return
 	}
 
+	if err := services.ResetFailedLogins(&user); err != nil {
+		utils.Log.Errorf("Не удалось сбросить счетчик неудачных входов пользователя %s: %v", user.Email, err)
+	}
+
 	utils.Log.Infof("Пользователь %s успешно вошел в систему", user.Email)
-	c.JSON(http.StatusOK, dto.AuthResponse{Token: tokenString})
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Token:                  tokens.AccessToken,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresIn:              tokens.ExpiresIn,
+		TwoFactorSetupRequired: services.TwoFactorRequired(&user),
+	})
+}
+
+// Refresh godoc
+// @Summary Обновление пары токенов
+// @Description Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен одноразовый: повторное использование отзывает все токены этого входа.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.RefreshInput true "Refresh-токен"
+// @Success 200 {object} dto.AuthResponse "Access- и refresh-токены"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Failure 401 {object} dto.ResponseError "Невалидный или повторно использованный refresh-токен"
+// @Failure 403 {object} dto.ResponseError "Учетная запись заблокирована или email не подтвержден"
+// @Router /auth/refresh [post]
+func Refresh(c *gin.Context) {
+	var input dto.RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при обновлении токена: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	tokens, user, err := services.RotateRefreshToken(input.RefreshToken, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Refresh-токен уже был использован, все сессии этого входа завершены"})
+		case errors.Is(err, services.ErrUserBanned):
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Учетная запись заблокирована"})
+		case errors.Is(err, services.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Email не подтвержден"})
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			utils.Log.Warn("Попытка обновления с невалидным refresh-токеном")
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Невалидный refresh-токен"})
+		default:
+			utils.Log.Errorf("Ошибка при обновлении токена: %v", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
+		}
+		return
+	}
+
+	utils.Log.Infof("Токены пользователя %s обновлены", user.Email)
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Отзывает текущий access-токен и завершает сессию этого входа вместе с ее refresh-токенами.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.LogoutInput false "Refresh-токен текущего входа"
+// @Success 200 {object} dto.ResponseMessage "Выход выполнен"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 500 {object} dto.ResponseError "Ошибка при отзыве токена"
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.LogoutInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		utils.Log.Warnf("Ошибка валидации при выходе: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	if input.RefreshToken != "" {
+		if err := services.RevokeRefreshTokenFamily(input.RefreshToken, userID); err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			utils.Log.Errorf("Ошибка при отзыве refresh-токена пользователя ID=%d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось отозвать токен"})
+			return
+		}
+	}
+
+	if err := services.RevokeToken(c.GetString("tokenID"), userID, c.GetTime("tokenExpiresAt")); err != nil {
+		utils.Log.Errorf("Ошибка при отзыве токена пользователя ID=%d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось отозвать токен"})
+		return
+	}
+
+	// Сессия завершается и без refresh-токена в запросе: он больше не обновится
+	if sessionID := c.GetUint("sessionID"); sessionID != 0 {
+		if _, err := services.RevokeSession(userID, sessionID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Log.Errorf("Ошибка при завершении сессии ID=%d пользователя ID=%d: %v", sessionID, userID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось отозвать токен"})
+			return
+		}
+	}
+
+	utils.Log.Infof("Пользователь ID=%d вышел из системы", userID)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Выход выполнен"})
+}
+
+// LogoutAll godoc
+// @Summary Выход на всех устройствах
+// @Description Завершает все сессии текущего пользователя, включая текущую.
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ResponseMessage "Все сессии завершены"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 500 {object} dto.ResponseError "Ошибка при завершении сессий"
+// @Router /auth/logout-all [post]
+func LogoutAll(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := services.RevokeAllUserSessions(userID); err != nil {
+		utils.Log.Errorf("Ошибка при завершении сессий пользователя ID=%d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось завершить сессии"})
+		return
+	}
+
+	// Текущий токен мог быть выдан в ту же секунду, что и отзыв, поэтому отзываем его явно
+	if err := services.RevokeToken(c.GetString("tokenID"), userID, c.GetTime("tokenExpiresAt")); err != nil {
+		utils.Log.Errorf("Ошибка при отзыве токена пользователя ID=%d: %v", userID, err)
+	}
+
+	logAudit(c, models.ActionAuthLogoutAll, models.TargetUser, userID, nil)
+
+	utils.Log.Infof("Пользователь ID=%d завершил все сессии", userID)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Все сессии завершены"})
+}
+
+// ForgotPassword godoc
+// @Summary Запрос на сброс пароля
+// @Description Отправляет на email одноразовую ссылку для сброса пароля. Ответ не зависит от того, зарегистрирован ли адрес.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ForgotPasswordInput true "Email пользователя"
+// @Success 200 {object} dto.ResponseMessage "Запрос принят"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Router /auth/forgot-password [post]
+func ForgotPassword(c *gin.Context) {
+	var input dto.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при запросе сброса пароля: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	// Ошибку не сообщаем клиенту, чтобы по ответу нельзя было определить наличие учетной записи или организации
+	organization, err := services.ResolveOrganization(input.Organization)
+	if err == nil {
+		err = services.RequestPasswordReset(organization.ID, input.Email)
+	}
+	if err != nil && !errors.Is(err, services.ErrUnknownOrganization) {
+		utils.Log.Errorf("Ошибка при запросе сброса пароля для %s: %v", input.Email, err)
+	}
+
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Если адрес зарегистрирован, на него отправлена ссылка для сброса пароля"})
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по одноразовому токену из письма и завершает все сессии пользователя.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ResetPasswordInput true "Токен из письма и новый пароль"
+// @Success 200 {object} dto.ResponseMessage "Пароль изменен"
+// @Failure 400 {object} dto.ResponseError "Недействительная ссылка, ошибка валидации или пароль не соответствует требованиям"
+// @Failure 500 {object} dto.ResponseError "Ошибка при смене пароля"
+// @Router /auth/reset-password [post]
+func ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при сбросе пароля: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	user, err := services.ResetPassword(input.Token, input.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			utils.Log.Warn("Попытка сброса пароля по недействительному токену")
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Ссылка для сброса пароля недействительна или устарела"})
+			return
+		}
+		if errors.Is(err, services.ErrPasswordPolicy) {
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+			return
+		}
+		utils.Log.Errorf("Ошибка при сбросе пароля: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить пароль"})
+		return
+	}
+
+	setOrganization(c, user.OrganizationID)
+	logAuditAs(c, user.ID, models.ActionAuthPasswordReset, models.TargetUser, user.ID, nil)
+
+	utils.Log.Infof("Пароль пользователя %s сброшен", user.Email)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Пароль изменен"})
+}
+
+// VerifyEmail godoc
+// @Summary Подтверждение email
+// @Description Подтверждает адрес электронной почты по подписанной ссылке из письма.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Токен из письма"
+// @Success 200 {object} dto.ResponseMessage "Email подтвержден"
+// @Failure 400 {object} dto.ResponseError "Недействительная или устаревшая ссылка"
+// @Failure 500 {object} dto.ResponseError "Ошибка при подтверждении email"
+// @Router /auth/verify-email [get]
+func VerifyEmail(c *gin.Context) {
+	var input dto.VerifyEmailQuery
+	if err := c.ShouldBindQuery(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при подтверждении email: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	user, err := services.VerifyEmail(input.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Email уже подтвержден"})
+		case errors.Is(err, services.ErrInvalidVerificationToken):
+			utils.Log.Warn("Попытка подтверждения email по недействительному токену")
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Ссылка для подтверждения email недействительна или устарела"})
+		default:
+			utils.Log.Errorf("Ошибка при подтверждении email: %v", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось подтвердить email"})
+		}
+		return
+	}
+
+	setOrganization(c, user.OrganizationID)
+	logAuditAs(c, user.ID, models.ActionAuthEmailVerified, models.TargetUser, user.ID, models.AuditChanges{}.
+		Add("email_verified", false, true))
+
+	utils.Log.Infof("Email пользователя %s подтвержден", user.Email)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Email подтвержден"})
+}
+
+// ResendVerification godoc
+// @Summary Повторная отправка письма для подтверждения email
+// @Description Отправляет новую ссылку для подтверждения email. Ответ не зависит от того, зарегистрирован ли адрес.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ResendVerificationInput true "Email пользователя"
+// @Success 200 {object} dto.ResponseMessage "Запрос принят"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Router /auth/resend-verification [post]
+func ResendVerification(c *gin.Context) {
+	var input dto.ResendVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при повторной отправке подтверждения: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	// Ошибку не сообщаем клиенту, чтобы по ответу нельзя было определить наличие учетной записи или организации
+	organization, err := services.ResolveOrganization(input.Organization)
+	if err == nil {
+		err = services.ResendVerification(organization.ID, input.Email)
+	}
+	if err != nil && !errors.Is(err, services.ErrUnknownOrganization) {
+		utils.Log.Errorf("Ошибка при повторной отправке подтверждения для %s: %v", input.Email, err)
+	}
+
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Если адрес зарегистрирован и не подтвержден, на него отправлена новая ссылка"})
 }
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strings"
 	"userManagement/internal/config"
 	"userManagement/internal/dto"
 	"userManagement/internal/models"
@@ -16,7 +18,7 @@ import (
 // @Tags Groups
 // @Accept json
 // @Produce json
-// @Param group body dto.GroupInput true "Название группы"
+// @Param group body dto.GroupInput true "Название группы и родительская группа"
 // @Success 201 {object} dto.ResponseMessage
 // @Failure 400 {object} dto.ResponseError
 // @Failure 403 {object} dto.ResponseError
@@ -33,32 +35,41 @@ func CreateGroups(c *gin.Context) {
 	input.Sanitize()
 
 	group := models.Group{
-		Name: input.Name,
+		OrganizationID: c.GetUint("organizationID"),
+		Name:           input.Name,
+		Description:    input.Description,
+		Metadata:       input.Metadata,
+		ParentID:       input.ParentID,
 	}
 
-	if err := config.DB.Create(&group).Error; err != nil {
-		utils.Log.Error("Ошибка при создании группы:", err)
-		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось создать группу"})
+	if err := services.CreateGroup(&group); err != nil {
+		respondGroupError(c, err, "Не удалось создать группу")
 		return
 	}
 
 	utils.Log.Infof("Создана группа: %s", group.Name)
 
-	if userID, exists := c.Get("userID"); exists {
-		err := services.LogAction(userID.(uint), fmt.Sprintf("Создана группа: %s", group.Name))
-		if err != nil {
-			utils.Log.Warn("Ошибка при логировании действия:", err)
-		}
+	changes := models.AuditChanges{}.Add("name", nil, group.Name)
+	if group.Description != "" {
+		changes.Add("description", nil, group.Description)
+	}
+	if len(group.Metadata) > 0 {
+		changes.Add("metadata", nil, group.Metadata)
 	}
+	if group.ParentID != nil {
+		changes.Add("parent_id", nil, *group.ParentID)
+	}
+	logAudit(c, models.ActionGroupCreated, models.TargetGroup, group.ID, changes)
 
 	c.JSON(http.StatusCreated, group)
 }
 
 // GetGroups godoc
 // @Summary Получение списка всех групп
+// @Description Участники каждой группы возвращаются вместе с ролью в ней (group_role): owner, manager или member.
 // @Tags Groups
 // @Produce json
-// @Success 200 {array} models.Group
+// @Success 200 {array} dto.GroupWithMembers
 // @Failure 403 {object} dto.ResponseError
 // @Router /groups [get]
 // @Security BearerAuth
@@ -66,23 +77,44 @@ func GetGroups(c *gin.Context) {
 	var groups []models.Group
 
 	// Загружаем все группы, включая пользователей
-	if err := config.DB.Preload("Users.Role").Find(&groups).Error; err != nil {
+	if err := tenantDB(c).Preload("Users.Role").Find(&groups).Error; err != nil {
 		utils.Log.Error("Ошибка при получении списка групп:", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при загрузке групп"})
 		return
 	}
 
+	groupIDs := make([]uint, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+	}
+	memberRoles, err := services.GroupMemberRoles(groupIDs)
+	if err != nil {
+		utils.Log.Error("Ошибка при получении ролей участников групп:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при загрузке групп"})
+		return
+	}
+
+	response := make([]dto.GroupWithMembers, len(groups))
+	for i, group := range groups {
+		members := make([]dto.GroupMemberInfo, len(group.Users))
+		for j, user := range group.Users {
+			members[j] = dto.GroupMemberInfo{User: user, GroupRole: memberRoles[group.ID][user.ID]}
+		}
+		response[i] = dto.GroupWithMembers{Group: group, Users: members}
+	}
+
 	utils.Log.Info("Получен список групп")
-	c.JSON(http.StatusOK, groups)
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateGroup godoc
-// @Summary Обновление названия группы
+// @Summary Обновление группы и ее места в иерархии
+// @Description Группу нельзя вложить в саму себя или в одну из ее подгрупп.
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Param id path int true "ID группы"
-// @Param group body dto.GroupInput true "Новое название"
+// @Param group body dto.GroupInput true "Новое название и родительская группа"
 // @Success 200 {object} dto.ResponseMessage
 // @Failure 400 {object} dto.ResponseError
 // @Failure 403 {object} dto.ResponseError
@@ -91,7 +123,7 @@ func GetGroups(c *gin.Context) {
 func UpdateGroup(c *gin.Context) {
 	id := c.Param("id")
 	var group models.Group
-	if err := config.DB.First(&group, id).Error; err != nil {
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
 		utils.Log.Warnf("Группа с ID %s не найдена", id)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
 		return
@@ -106,24 +138,30 @@ func UpdateGroup(c *gin.Context) {
 
 	input.Sanitize()
 
-	oldName := group.Name
+	old := group
 	group.Name = input.Name
-	config.DB.Save(&group)
+	group.Description = input.Description
+	group.Metadata = input.Metadata
+	group.ParentID = input.ParentID
+	if err := services.SaveGroup(&group); err != nil {
+		respondGroupError(c, err, "Не удалось обновить группу")
+		return
+	}
 
-	utils.Log.Infof("Обновлена группа %s -> %s", oldName, group.Name)
+	utils.Log.Infof("Обновлена группа %s -> %s", old.Name, group.Name)
 
-	if userID, exists := c.Get("userID"); exists {
-		err := services.LogAction(userID.(uint), fmt.Sprintf("Обновлена группа: %s -> %s", oldName, group.Name))
-		if err != nil {
-			utils.Log.Warn("Ошибка при логировании действия:", err)
-		}
-	}
+	logAudit(c, models.ActionGroupUpdated, models.TargetGroup, group.ID, models.AuditChanges{}.
+		Add("name", old.Name, group.Name).
+		Add("description", old.Description, group.Description).
+		Add("metadata", map[string]string(old.Metadata), map[string]string(group.Metadata)).
+		Add("parent_id", old.ParentID, group.ParentID))
 
 	c.JSON(http.StatusOK, group)
 }
 
 // DeleteGroup godoc
 // @Summary Удаление группы
+// @Description Подгруппы удаляемой группы переходят к ее родителю.
 // @Tags Groups
 // @Produce json
 // @Param id path int true "ID группы"
@@ -135,21 +173,14 @@ func DeleteGroup(c *gin.Context) {
 	id := c.Param("id")
 
 	var group models.Group
-	if err := config.DB.First(&group, id).Error; err != nil {
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
 		utils.Log.Warnf("Попытка удалить несуществующую группу с ID %s", id)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
 		return
 	}
 
-	// Удалить связи с пользователями
-	if err := config.DB.Model(&group).Association("Users").Clear(); err != nil {
-		utils.Log.Error("Ошибка при удалении связей с пользователями:", err)
-		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить связи с пользователями"})
-		return
-	}
-
-	// Удалить группу
-	if err := config.DB.Unscoped().Delete(&group).Error; err != nil {
+	// Связи с пользователями, перенос подгрупп и удаление выполняются одной транзакцией
+	if err := services.DeleteGroup(group); err != nil {
 		utils.Log.Error("Ошибка при удалении группы:", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить группу"})
 		return
@@ -157,33 +188,30 @@ func DeleteGroup(c *gin.Context) {
 
 	utils.Log.Infof("Удалена группа %s", group.Name)
 
-	if userID, exists := c.Get("userID"); exists {
-		err := services.LogAction(userID.(uint), fmt.Sprintf("Удалил группу %s", group.Name))
-		if err != nil {
-			utils.Log.Warn("Ошибка при логировании действия:", err)
-		}
-	}
+	logAudit(c, models.ActionGroupDeleted, models.TargetGroup, group.ID, models.AuditChanges{}.
+		Add("name", group.Name, nil))
 
 	c.JSON(http.StatusOK, dto.ResponseError{Message: "Группа успешно удалена"})
 }
 
 // AddUserToGroup godoc
 // @Summary Добавление пользователя в группу
+// @Description Доступно при праве groups:write, а также владельцу и менеджеру группы.
+// @Description Менеджер может добавлять только обычных участников (member).
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Param id path int true "ID группы"
-// @Param user body dto.UserGroupInput true "ID пользователя для добавления"
+// @Param user body dto.UserGroupInput true "ID пользователя и его роль в группе"
 // @Success 200 {object} dto.ResponseMessage
 // @Failure 400 {object} dto.ResponseError
 // @Failure 403 {object} dto.ResponseError
+// @Failure 409 {object} dto.ResponseError "Пользователь уже состоит в группе"
 // @Router /groups/{id}/users [post]
 // @Security BearerAuth
 func AddUserToGroup(c *gin.Context) {
 	groupID := c.Param("id")
-	var input struct {
-		UserID uint `json:"user_id"`
-	}
+	var input dto.UserGroupInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		utils.Log.Warn("Некорректный ввод при добавлении пользователя в группу:", err)
@@ -191,40 +219,53 @@ func AddUserToGroup(c *gin.Context) {
 		return
 	}
 
+	role := input.Role
+	if role == "" {
+		role = models.GroupRoleMember
+	}
+	if !canManageGroupMember(c, role) {
+		utils.Log.Warnf("Пользователь ID=%d не может добавить в группу %s участника с ролью %s", c.GetUint("userID"), groupID, role)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Менеджер группы может управлять только обычными участниками"})
+		return
+	}
+
 	var group models.Group
-	if err := config.DB.Preload("Users").First(&group, groupID).Error; err != nil {
-		utils.Log.Warnf("Группа с ID %s не найдена", groupID)
+	if err := tenantDB(c).First(&group, groupID).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", groupID)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
 		return
 	}
 
 	var user models.User
-	if err := config.DB.First(&user, input.UserID).Error; err != nil {
+	if err := tenantDB(c).First(&user, input.UserID).Error; err != nil {
 		utils.Log.Warnf("Пользователь с ID %d не найден", input.UserID)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
-	if err := config.DB.Model(&group).Association("Users").Append(&user); err != nil {
+	if err := services.AddGroupMember(group.ID, user.ID, role); err != nil {
+		if errors.Is(err, services.ErrAlreadyGroupMember) {
+			c.JSON(http.StatusConflict, dto.ResponseError{Message: "Пользователь уже состоит в группе"})
+			return
+		}
 		utils.Log.Error("Ошибка при добавлении пользователя в группу:", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось добавить пользователя в группу"})
 		return
 	}
 
-	utils.Log.Infof("Добавлен пользователь %s в группу %s", user.Name, group.Name)
+	utils.Log.Infof("Добавлен пользователь %s в группу %s с ролью %s", user.Name, group.Name, role)
 
-	if userID, exists := c.Get("userID"); exists {
-		err := services.LogAction(userID.(uint), fmt.Sprintf("Добавлен пользователь %s в группу %s", user.Name, group.Name))
-		if err != nil {
-			utils.Log.Warn("Ошибка при логировании действия:", err)
-		}
-	}
+	logAudit(c, models.ActionGroupMemberAdded, models.TargetGroup, group.ID, models.AuditChanges{}.
+		Add("user_id", nil, user.ID).
+		Add("role", nil, role))
 
 	c.JSON(http.StatusOK, dto.ResponseError{Message: "Пользователь добавлен в группу"})
 }
 
 // RemoveUserFromGroup godoc
 // @Summary Удаление пользователя из группы
+// @Description Доступно при праве groups:write, а также владельцу и менеджеру группы.
+// @Description Менеджер может исключать только обычных участников, последнего владельца может исключить только администратор.
 // @Tags Groups
 // @Produce json
 // @Param id path int true "ID группы"
@@ -239,47 +280,504 @@ func RemoveUserFromGroup(c *gin.Context) {
 	userId := c.Param("user_id")
 
 	var group models.Group
-	if err := config.DB.Preload("Users").First(&group, groupId).Error; err != nil {
+	if err := tenantDB(c).First(&group, groupId).Error; err != nil {
 		utils.Log.Warnf("Группа с ID %s не найдена", groupId)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
 		return
 	}
 
 	var user models.User
-	if err := config.DB.First(&user, userId).Error; err != nil {
+	if err := tenantDB(c).First(&user, userId).Error; err != nil {
 		utils.Log.Warnf("Пользователь с ID %s не найден", userId)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
 	// Проверка: состоит ли пользователь в группе
-	found := false
-	for _, u := range group.Users {
-		if u.ID == user.ID {
-			found = true
-			break
+	role, err := services.GroupMemberRole(group.ID, user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) {
+			utils.Log.Warn("Попытка удалить пользователя, которого нет в группе")
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь не состоит в группе"})
+			return
 		}
+		utils.Log.Error("Ошибка при проверке членства в группе:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить пользователя из группы"})
+		return
 	}
-	if !found {
-		utils.Log.Warn("Попытка удалить пользователя, которого нет в группе")
-		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь не состоит в группе"})
+	if !canManageGroupMember(c, role) {
+		utils.Log.Warnf("Пользователь ID=%d не может исключить из группы %s участника с ролью %s", c.GetUint("userID"), groupId, role)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Менеджер группы может управлять только обычными участниками"})
 		return
 	}
 
-	if err := config.DB.Model(&group).Association("Users").Unscoped().Delete(&user); err != nil {
-		utils.Log.Error("Ошибка при удалении пользователя из группы:", err)
-		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить пользователя из группы"})
+	// Владельцы и менеджеры не могут оставить группу без владельца, это может только администратор
+	if _, err := services.RemoveGroupMember(group.ID, user.ID, c.GetString("groupRole") != ""); err != nil {
+		switch {
+		case errors.Is(err, services.ErrLastGroupOwner):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		case errors.Is(err, services.ErrNotGroupMember):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь не состоит в группе"})
+		default:
+			utils.Log.Error("Ошибка при удалении пользователя из группы:", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить пользователя из группы"})
+		}
 		return
 	}
 
 	utils.Log.Infof("Удален пользователь %s из группы %s", user.Name, group.Name)
 
-	if userID, exists := c.Get("userID"); exists {
-		err := services.LogAction(userID.(uint), fmt.Sprintf("Удален пользователь %s из группы %s", user.Name, group.Name))
-		if err != nil {
-			utils.Log.Warn("Ошибка при логировании действия:", err)
+	logAudit(c, models.ActionGroupMemberRemoved, models.TargetGroup, group.ID, models.AuditChanges{}.
+		Add("user_id", user.ID, nil).
+		Add("role", role, nil))
+
+	c.JSON(http.StatusOK, dto.ResponseError{Message: "Пользователь удален из группы"})
+}
+
+// UpdateGroupMemberRole godoc
+// @Summary Изменение роли участника группы
+// @Description Доступно при праве groups:write и владельцу группы. Владелец не может понизить последнего владельца.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param user_id path int true "ID пользователя"
+// @Param role body dto.GroupMemberRoleInput true "Новая роль в группе"
+// @Success 200 {object} dto.ResponseMessage
+// @Failure 400 {object} dto.ResponseError
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /groups/{id}/users/{user_id} [patch]
+// @Security BearerAuth
+func UpdateGroupMemberRole(c *gin.Context) {
+	groupId := c.Param("id")
+	userId := c.Param("user_id")
+
+	var input dto.GroupMemberRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warn("Некорректный ввод при изменении роли участника группы:", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	var group models.Group
+	if err := tenantDB(c).First(&group, groupId).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", groupId)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
+		return
+	}
+
+	var user models.User
+	if err := tenantDB(c).First(&user, userId).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден", userId)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	previous, err := services.SetGroupMemberRole(group.ID, user.ID, input.Role, c.GetString("groupRole") != "")
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrLastGroupOwner):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		default:
+			utils.Log.Error("Ошибка при изменении роли участника группы:", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить роль участника"})
 		}
+		return
 	}
 
-	c.JSON(http.StatusOK, dto.ResponseError{Message: "Пользователь удален из группы"})
+	utils.Log.Infof("Роль пользователя %s в группе %s изменена: %s -> %s", user.Name, group.Name, previous, input.Role)
+
+	if previous != input.Role {
+		logAudit(c, models.ActionGroupMemberRole, models.TargetGroup, group.ID, models.AuditChanges{}.
+			Add("user_id", nil, user.ID).
+			Add("role", previous, input.Role))
+	}
+
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Роль участника изменена"})
+}
+
+// respondGroupError переводит ошибки сохранения группы в HTTP-ответы
+func respondGroupError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrParentGroupNotFound), errors.Is(err, services.ErrGroupCycle):
+		utils.Log.Warnf("Некорректная родительская группа: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+	default:
+		utils.Log.Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: message})
+	}
+}
+
+// canManageGroupMember проверяет, может ли текущий пользователь добавить или исключить участника с ролью role.
+// Право groups:write и роль владельца позволяют управлять всеми участниками, менеджер управляет только обычными.
+func canManageGroupMember(c *gin.Context, role string) bool {
+	switch c.GetString("groupRole") {
+	case "", models.GroupRoleOwner:
+		return true
+	case models.GroupRoleManager:
+		return role == models.GroupRoleMember
+	}
+	return false
+}
+
+// GetEffectiveGroupMembers godoc
+// @Summary Участники группы с учетом подгрупп
+// @Description Возвращает прямых участников группы и участников всех ее подгрупп на любой глубине.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Success 200 {array} models.User
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /groups/{id}/effective-members [get]
+// @Security BearerAuth
+func GetEffectiveGroupMembers(c *gin.Context) {
+	id := c.Param("id")
+
+	var group models.Group
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
+		return
+	}
+
+	users, err := services.EffectiveGroupMembers(group.ID)
+	if err != nil {
+		utils.Log.Error("Ошибка при получении участников группы с учетом подгрупп:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить участников группы"})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// GetEffectiveUserGroups godoc
+// @Summary Группы пользователя с учетом вложенности
+// @Description Возвращает группы, в которых пользователь состоит напрямую, и все их родительские группы.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {array} models.Group
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /users/{id}/effective-groups [get]
+// @Security BearerAuth
+func GetEffectiveUserGroups(c *gin.Context) {
+	id := c.Param("id")
+
+	var user models.User
+	if err := tenantDB(c).First(&user, id).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	groups, err := services.EffectiveUserGroups(user.ID)
+	if err != nil {
+		utils.Log.Error("Ошибка при получении групп пользователя с учетом вложенности:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить группы пользователя"})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetGroup godoc
+// @Summary Получение группы по ID
+// @Description Возвращает группу с описанием, метаданными, количеством прямых участников и подгруппами без списка участников.
+// @Description Доступно при праве groups:read и участникам самой группы.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Success 200 {object} dto.GroupDetails
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /groups/{id} [get]
+// @Security BearerAuth
+func GetGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	var group models.Group
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
+		return
+	}
+
+	details := dto.GroupDetails{Group: group, Subgroups: []models.Group{}}
+	// Считаем так же, как GET /groups/:id/users: удаленные пользователи в число участников не входят
+	members := config.DB.Table("group_users").Select("user_id").Where("group_id = ?", group.ID)
+	if err := tenantDB(c).Model(&models.User{}).Where("id IN (?)", members).Count(&details.MemberCount).Error; err != nil {
+		utils.Log.Error("Ошибка при подсчете участников группы:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при загрузке группы"})
+		return
+	}
+	if err := tenantDB(c).Where("parent_id = ?", group.ID).Order("name").Find(&details.Subgroups).Error; err != nil {
+		utils.Log.Error("Ошибка при получении подгрупп:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при загрузке группы"})
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// GetGroupUsers godoc
+// @Summary Постраничный список участников группы
+// @Description Возвращает прямых участников группы с их ролью в ней. Доступно при праве groups:read и участникам самой группы.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не более 100)" default(20)
+// @Param role query string false "Роль в группе" Enums(owner, manager, member)
+// @Param search query string false "Поиск по имени и email"
+// @Success 200 {object} dto.PageResponse{data=[]dto.GroupMemberInfo}
+// @Failure 400 {object} dto.ResponseError
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /groups/{id}/users [get]
+// @Security BearerAuth
+func GetGroupUsers(c *gin.Context) {
+	id := c.Param("id")
+
+	var params dto.GroupMembersQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Log.Warnf("Некорректные параметры списка участников группы: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+	params.Normalize()
+
+	var group models.Group
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
+		return
+	}
+
+	members := config.DB.Table("group_users").Select("user_id").Where("group_id = ?", group.ID)
+	if params.Role != "" {
+		members = members.Where("role = ?", params.Role)
+	}
+	query := tenantDB(c).Model(&models.User{}).Where("id IN (?)", members)
+	if search := strings.TrimSpace(params.Search); search != "" {
+		pattern := likePattern(search)
+		query = query.Where("name ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+
+	page, total, err := paginate(query, params.PaginationQuery)
+	if err != nil {
+		utils.Log.Errorf("Ошибка при подсчете участников группы: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении участников группы"})
+		return
+	}
+
+	var users []models.User
+	if err := page.Order("name").Order("id").Preload("Role").Find(&users).Error; err != nil {
+		utils.Log.Errorf("Ошибка при получении участников группы: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении участников группы"})
+		return
+	}
+
+	userIDs := make([]uint, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	roles, err := services.GroupMemberRolesOf(group.ID, userIDs)
+	if err != nil {
+		utils.Log.Errorf("Ошибка при получении ролей участников группы: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении участников группы"})
+		return
+	}
+
+	result := make([]dto.GroupMemberInfo, len(users))
+	for i, user := range users {
+		result[i] = dto.GroupMemberInfo{User: user, GroupRole: roles[user.ID]}
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(result, params.PaginationQuery, total))
+}
+
+// UpdateGroupMembers godoc
+// @Summary Добавление и исключение нескольких участников группы
+// @Description Все изменения применяются в одной транзакции. Уже состоящие в группе при добавлении и не состоящие
+// @Description при исключении пропускаются. Права те же, что при добавлении и исключении по одному:
+// @Description менеджер управляет только обычными участниками, а владельцы и менеджеры не могут исключить последнего владельца.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param members body dto.GroupMembersInput true "Добавляемые участники с ролями и ID исключаемых"
+// @Success 200 {object} dto.GroupMembersResult
+// @Failure 400 {object} dto.ResponseError
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /groups/{id}/users [patch]
+// @Security BearerAuth
+func UpdateGroupMembers(c *gin.Context) {
+	id := c.Param("id")
+
+	var input dto.GroupMembersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warn("Некорректный ввод при изменении состава группы:", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	var group models.Group
+	if err := tenantDB(c).First(&group, id).Error; err != nil {
+		utils.Log.Warnf("Группа с ID %s не найдена", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Группа не найдена"})
+		return
+	}
+
+	// Собираем всех затронутых пользователей, чтобы одним запросом убедиться, что они есть в организации
+	userIDs := make([]uint, 0, len(input.Add)+len(input.Remove))
+	seen := make(map[uint]bool)
+	add := make([]models.GroupMember, 0, len(input.Add))
+	for _, entry := range input.Add {
+		role := entry.Role
+		if role == "" {
+			role = models.GroupRoleMember
+		}
+		if !canManageGroupMember(c, role) {
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Менеджер группы может управлять только обычными участниками"})
+			return
+		}
+		if seen[entry.UserID] {
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: fmt.Sprintf("Пользователь %d указан несколько раз", entry.UserID)})
+			return
+		}
+		seen[entry.UserID] = true
+		userIDs = append(userIDs, entry.UserID)
+		add = append(add, models.GroupMember{UserID: entry.UserID, Role: role})
+	}
+	for _, userID := range input.Remove {
+		if seen[userID] {
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: fmt.Sprintf("Пользователь %d указан несколько раз", userID)})
+			return
+		}
+		seen[userID] = true
+		userIDs = append(userIDs, userID)
+	}
+
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusOK, dto.GroupMembersResult{Added: []uint{}, Removed: []uint{}})
+		return
+	}
+
+	var found int64
+	if err := tenantDB(c).Model(&models.User{}).Where("id IN ?", userIDs).Count(&found).Error; err != nil {
+		utils.Log.Error("Ошибка при проверке пользователей:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить состав группы"})
+		return
+	}
+	if found != int64(len(userIDs)) {
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	// Менеджер не может исключать владельцев и других менеджеров
+	currentRoles, err := services.GroupMemberRolesOf(group.ID, input.Remove)
+	if err != nil {
+		utils.Log.Error("Ошибка при проверке членства в группе:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить состав группы"})
+		return
+	}
+	for _, role := range currentRoles {
+		if !canManageGroupMember(c, role) {
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Менеджер группы может управлять только обычными участниками"})
+			return
+		}
+	}
+
+	added, removed, err := services.UpdateGroupMembers(group.ID, add, input.Remove, c.GetString("groupRole") != "")
+	if err != nil {
+		if errors.Is(err, services.ErrLastGroupOwner) {
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+			return
+		}
+		utils.Log.Error("Ошибка при изменении состава группы:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить состав группы"})
+		return
+	}
+
+	addedRoles := make(map[uint]string, len(add))
+	for _, member := range add {
+		addedRoles[member.UserID] = member.Role
+	}
+	for _, userID := range added {
+		logAudit(c, models.ActionGroupMemberAdded, models.TargetGroup, group.ID, models.AuditChanges{}.
+			Add("user_id", nil, userID).
+			Add("role", nil, addedRoles[userID]))
+	}
+	for _, userID := range removed {
+		logAudit(c, models.ActionGroupMemberRemoved, models.TargetGroup, group.ID, models.AuditChanges{}.
+			Add("user_id", userID, nil).
+			Add("role", currentRoles[userID], nil))
+	}
+
+	utils.Log.Infof("Изменен состав группы %s: добавлено %d, исключено %d", group.Name, len(added), len(removed))
+
+	result := dto.GroupMembersResult{Added: added, Removed: removed}
+	if result.Added == nil {
+		result.Added = []uint{}
+	}
+	if result.Removed == nil {
+		result.Removed = []uint{}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetUserGroups godoc
+// @Summary Группы, в которых пользователь состоит напрямую
+// @Description Возвращает группы пользователя вместе с его ролью в каждой из них.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {array} dto.UserGroupInfo
+// @Failure 403 {object} dto.ResponseError
+// @Failure 404 {object} dto.ResponseError
+// @Router /users/{id}/groups [get]
+// @Security BearerAuth
+func GetUserGroups(c *gin.Context) {
+	id := c.Param("id")
+
+	var user models.User
+	if err := tenantDB(c).First(&user, id).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден", id)
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	var memberships []models.GroupMember
+	if err := config.DB.Where("user_id = ?", user.ID).Find(&memberships).Error; err != nil {
+		utils.Log.Error("Ошибка при получении членства пользователя в группах:", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить группы пользователя"})
+		return
+	}
+
+	roles := make(map[uint]string, len(memberships))
+	groupIDs := make([]uint, len(memberships))
+	for i, membership := range memberships {
+		roles[membership.GroupID] = membership.Role
+		groupIDs[i] = membership.GroupID
+	}
+
+	var groups []models.Group
+	if len(groupIDs) > 0 {
+		if err := tenantDB(c).Where("id IN ?", groupIDs).Order("name").Find(&groups).Error; err != nil {
+			utils.Log.Error("Ошибка при получении групп пользователя:", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить группы пользователя"})
+			return
+		}
+	}
+
+	result := make([]dto.UserGroupInfo, len(groups))
+	for i, group := range groups {
+		result[i] = dto.UserGroupInfo{Group: group, GroupRole: roles[group.ID]}
+	}
+
+	c.JSON(http.StatusOK, result)
 }
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonateUser godoc
+// @Summary Вход от имени пользователя
+// @Description Выдает сотруднику поддержки короткоживущий токен пользователя с claim act. По токену доступно то же,
+// @Description что видит пользователь, кроме смены пароля, 2FA, ключей, сессий и ролей. Каждый запрос по токену
+// @Description записывается в журнал активности от имени сотрудника. Refresh-токен не выдается.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param input body dto.ImpersonateInput true "Причина входа"
+// @Success 200 {object} dto.ImpersonationResponse
+// @Failure 400 {object} dto.ResponseError "Ошибка валидации, попытка войти от своего имени или пользователь заблокирован"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав или пользователь сам может входить от имени других"
+// @Failure 404 {object} dto.ResponseError "Пользователь не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при создании токена"
+// @Router /users/{id}/impersonate [post]
+func ImpersonateUser(c *gin.Context) {
+	actorID := c.GetUint("userID")
+
+	var input dto.ImpersonateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при входе от имени пользователя: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+	input.Reason = utils.SanitizeInput(input.Reason)
+
+	var actor models.User
+	if err := tenantDB(c).Preload("Role.Permissions").First(&actor, actorID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	var target models.User
+	if err := tenantDB(c).Preload("Role.Permissions").First(&target, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для входа от его имени", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	token, jti, err := services.IssueImpersonationToken(actor, target)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImpersonateSelf):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Нельзя войти от имени самого себя"})
+		case errors.Is(err, services.ErrUserBanned):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь заблокирован"})
+		case errors.Is(err, services.ErrImpersonatePrivileged):
+			utils.Log.Warnf("Сотрудник ID=%d пытался войти от имени привилегированного пользователя ID=%d", actorID, target.ID)
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Нельзя войти от имени этого пользователя"})
+		default:
+			utils.Log.Errorf("Не удалось выдать токен входа от имени пользователя ID=%d: %v", target.ID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
+		}
+		return
+	}
+
+	logAudit(c, models.ActionUserImpersonated, models.TargetUser, target.ID, models.AuditChanges{}.
+		Add("reason", nil, input.Reason).
+		Add("token_id", nil, jti).
+		Add("expires_in", nil, int64(config.ImpersonationTTL.Seconds())))
+
+	utils.Log.Infof("Сотрудник %s вошел от имени пользователя %s", actor.Email, target.Email)
+	c.JSON(http.StatusOK, dto.ImpersonationResponse{
+		Token:     token,
+		ExpiresIn: int64(config.ImpersonationTTL.Seconds()),
+		UserID:    target.ID,
+	})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+	"userManagement/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary Открытые ключи подписи токенов
+// @Description Набор открытых ключей (JWKS) для проверки access-токенов другими сервисами. Ключ выбирается по kid из заголовка токена.
+// @Description При подписи общим секретом HS256 набор пуст.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} keyring.JWKSet
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *gin.Context) {
+	// Ключи меняются редко, но после ротации клиенты должны увидеть новый ключ без долгой задержки
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, config.Keys.JWKS())
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateOAuthClient godoc
+// @Summary Регистрация клиента OpenID Connect
+// @Description Регистрирует приложение, использующее сервис как провайдер идентификации. Секрет конфиденциального клиента возвращается один раз.
+// @Tags OIDC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.OAuthClientInput true "Параметры клиента"
+// @Success 201 {object} dto.OAuthClientCreatedResponse
+// @Failure 400 {object} dto.ResponseError "Неверный ввод"
+// @Failure 500 {object} dto.ResponseError "Ошибка при регистрации клиента"
+// @Router /oauth/clients [post]
+func CreateOAuthClient(c *gin.Context) {
+	var input dto.OAuthClientInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Некорректный ввод при регистрации клиента OIDC: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	client, secret, err := services.CreateOAuthClient(input.Name, input.RedirectURIs, input.Public, input.FirstParty)
+	if err != nil {
+		utils.Log.Errorf("Не удалось зарегистрировать клиента OIDC %s: %v", input.Name, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось зарегистрировать клиента"})
+		return
+	}
+
+	logAudit(c, models.ActionOAuthClientCreated, models.TargetOAuthClient, client.ID, models.AuditChanges{}.
+		Add("name", nil, client.Name).
+		Add("client_id", nil, client.ClientID).
+		Add("redirect_uris", nil, client.RedirectURIs).
+		Add("public", nil, client.Public).
+		Add("first_party", nil, client.FirstParty))
+
+	utils.Log.Infof("Зарегистрирован клиент OIDC %s (%s)", client.Name, client.ClientID)
+	c.JSON(http.StatusCreated, dto.OAuthClientCreatedResponse{Client: client, ClientSecret: secret})
+}
+
+// GetOAuthClients godoc
+// @Summary Список клиентов OpenID Connect
+// @Tags OIDC
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.OAuthClient
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении клиентов"
+// @Router /oauth/clients [get]
+func GetOAuthClients(c *gin.Context) {
+	var clients []models.OAuthClient
+	if err := config.DB.Order("id").Find(&clients).Error; err != nil {
+		utils.Log.Errorf("Не удалось получить список клиентов OIDC: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить клиентов"})
+		return
+	}
+
+	c.JSON(http.StatusOK, clients)
+}
+
+// DeleteOAuthClient godoc
+// @Summary Удаление клиента OpenID Connect
+// @Description Удаляет клиента вместе с невыданными кодами авторизации и согласиями пользователей.
+// @Description Уже выданные клиенту access-токены действуют до истечения срока.
+// @Tags OIDC
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID клиента"
+// @Success 200 {object} dto.ResponseMessage "Клиент удален"
+// @Failure 404 {object} dto.ResponseError "Клиент не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при удалении клиента"
+// @Router /oauth/clients/{id} [delete]
+func DeleteOAuthClient(c *gin.Context) {
+	var client models.OAuthClient
+	if err := config.DB.First(&client, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Клиент OIDC с ID %s не найден", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Клиент не найден"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&models.AuthorizationCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&models.OAuthConsent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&client).Error
+	})
+	if err != nil {
+		utils.Log.Errorf("Не удалось удалить клиента OIDC %s: %v", client.ClientID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить клиента"})
+		return
+	}
+
+	logAudit(c, models.ActionOAuthClientDeleted, models.TargetOAuthClient, client.ID, models.AuditChanges{}.
+		Add("name", client.Name, nil).
+		Add("client_id", client.ClientID, nil))
+
+	utils.Log.Infof("Клиент OIDC %s удален", client.ClientID)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Клиент удален"})
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorize godoc
+// @Summary Запрос авторизации OpenID Connect
+// @Description Выдает код авторизации клиенту (authorization code flow с обязательным PKCE S256) и возвращает адрес возврата
+// @Description на redirect_uri (dto.AuthorizeRedirect), куда страница входа перенаправляет браузер.
+// @Description Пользователь должен быть авторизован. Браузер без токена перенаправляется на OIDC_LOGIN_URL с теми же параметрами.
+// @Description Для сторонних клиентов без сохраненного согласия возвращается dto.ConsentPrompt.
+// @Tags OIDC
+// @Security BearerAuth
+// @Produce json
+// @Param response_type query string true "Тип ответа" Enums(code)
+// @Param client_id query string true "Идентификатор клиента"
+// @Param redirect_uri query string true "Адрес возврата, зарегистрированный у клиента"
+// @Param scope query string true "Области доступа, обязательно openid"
+// @Param state query string false "Значение, возвращаемое клиенту без изменений"
+// @Param nonce query string false "Значение, включаемое в ID-токен"
+// @Param code_challenge query string true "PKCE challenge"
+// @Param code_challenge_method query string true "Метод PKCE" Enums(S256)
+// @Success 200 {object} dto.ConsentPrompt "Требуется согласие пользователя, иначе dto.AuthorizeRedirect"
+// @Success 302 "Перенаправление браузера без токена на страницу входа OIDC_LOGIN_URL"
+// @Failure 400 {object} dto.OAuthError "Неизвестный клиент или redirect_uri"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Router /oauth/authorize [get]
+func Authorize(c *gin.Context) {
+	var req dto.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.Log.Warnf("Некорректный запрос авторизации: %v", err)
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+
+	handleAuthorize(c, req, nil)
+}
+
+// AuthorizeConsent godoc
+// @Summary Согласие на доступ стороннего клиента
+// @Description Принимает те же параметры, что и GET /oauth/authorize, и решение пользователя. При согласии выдает код авторизации.
+// @Tags OIDC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.ConsentInput true "Параметры запроса авторизации и решение пользователя"
+// @Success 200 {object} dto.AuthorizeRedirect "Адрес возврата на redirect_uri с кодом или ошибкой access_denied"
+// @Failure 400 {object} dto.OAuthError "Неизвестный клиент или redirect_uri"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Router /oauth/authorize [post]
+func AuthorizeConsent(c *gin.Context) {
+	var input dto.ConsentInput
+	if err := c.ShouldBind(&input); err != nil {
+		utils.Log.Warnf("Некорректный ответ на запрос согласия: %v", err)
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+
+	handleAuthorize(c, input.AuthorizeRequest, &input.Approve)
+}
+
+// handleAuthorize проверяет запрос авторизации и выдает код. approve равен nil, если пользователь еще не отвечал на запрос согласия.
+func handleAuthorize(c *gin.Context, req dto.AuthorizeRequest, approve *bool) {
+	client, err := services.GetOAuthClient(req.ClientID)
+	if err != nil {
+		utils.Log.Warnf("Запрос авторизации от неизвестного клиента %s", req.ClientID)
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_request", ErrorDescription: "Неизвестный клиент"})
+		return
+	}
+
+	// На незарегистрированный адрес не перенаправляем даже ошибки, иначе им можно воспользоваться для фишинга
+	if !client.RedirectURIs.Contains(req.RedirectURI) {
+		utils.Log.Warnf("Клиент %s запросил незарегистрированный redirect_uri %s", client.ClientID, req.RedirectURI)
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_request", ErrorDescription: "redirect_uri не зарегистрирован для клиента"})
+		return
+	}
+
+	if req.ResponseType != "code" {
+		redirectWithParams(c, req.RedirectURI, req.State, url.Values{
+			"error":             {"unsupported_response_type"},
+			"error_description": {"Поддерживается только response_type=code"},
+		})
+		return
+	}
+
+	scope, err := services.NormalizeScope(req.Scope)
+	if err != nil {
+		redirectWithParams(c, req.RedirectURI, req.State, url.Values{
+			"error":             {"invalid_scope"},
+			"error_description": {"Область openid обязательна, допустимы openid, profile, email"},
+		})
+		return
+	}
+
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != services.PKCEMethodS256 {
+		redirectWithParams(c, req.RedirectURI, req.State, url.Values{
+			"error":             {"invalid_request"},
+			"error_description": {"Требуется PKCE с code_challenge_method=S256"},
+		})
+		return
+	}
+
+	userID := c.GetUint("userID")
+
+	// Собственным приложениям согласие не требуется
+	if !client.FirstParty {
+		if approve == nil {
+			granted, err := services.HasConsent(userID, client.ClientID, scope)
+			if err != nil {
+				utils.Log.Errorf("Ошибка при проверке согласия пользователя ID=%d: %v", userID, err)
+				c.JSON(http.StatusInternalServerError, dto.OAuthError{Error: "server_error"})
+				return
+			}
+			if !granted {
+				c.JSON(http.StatusOK, dto.ConsentPrompt{
+					ClientID:   client.ClientID,
+					ClientName: client.Name,
+					Scopes:     strings.Fields(scope),
+					Message:    "Подтвердите доступ приложения к данным учетной записи",
+				})
+				return
+			}
+		} else if !*approve {
+			utils.Log.Infof("Пользователь ID=%d отказал в доступе клиенту %s", userID, client.ClientID)
+			redirectWithParams(c, req.RedirectURI, req.State, url.Values{"error": {"access_denied"}})
+			return
+		} else {
+			if err := services.GrantConsent(userID, client.ClientID, scope); err != nil {
+				utils.Log.Errorf("Не удалось сохранить согласие пользователя ID=%d: %v", userID, err)
+				c.JSON(http.StatusInternalServerError, dto.OAuthError{Error: "server_error"})
+				return
+			}
+			logAudit(c, models.ActionOAuthConsent, models.TargetOAuthClient, client.ID, models.AuditChanges{}.
+				Add("scope", nil, scope))
+		}
+	}
+
+	code, err := services.CreateAuthorizationCode(client, userID, req.RedirectURI, scope, req.Nonce, req.CodeChallenge)
+	if err != nil {
+		utils.Log.Errorf("Не удалось создать код авторизации: %v", err)
+		redirectWithParams(c, req.RedirectURI, req.State, url.Values{"error": {"server_error"}})
+		return
+	}
+
+	utils.Log.Infof("Пользователю ID=%d выдан код авторизации для клиента %s", userID, client.ClientID)
+	redirectWithParams(c, req.RedirectURI, req.State, url.Values{"code": {code}})
+}
+
+// redirectWithParams возвращает адрес redirect_uri с параметрами ответа и state, на который страница входа перенаправляет браузер
+func redirectWithParams(c *gin.Context, redirectURI, state string, params url.Values) {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_request", ErrorDescription: "Некорректный redirect_uri"})
+		return
+	}
+
+	query := target.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	target.RawQuery = query.Encode()
+
+	c.JSON(http.StatusOK, dto.AuthorizeRedirect{RedirectTo: target.String()})
+}
+
+// Token godoc
+// @Summary Обмен кода авторизации на токены
+// @Description Token endpoint OpenID Connect. Конфиденциальные клиенты аутентифицируются секретом (HTTP Basic или client_secret),
+// @Description публичные передают только client_id. Код проверяется вместе с redirect_uri и code_verifier.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Тип гранта" Enums(authorization_code)
+// @Param code formData string true "Код авторизации"
+// @Param redirect_uri formData string true "Адрес возврата из запроса авторизации"
+// @Param code_verifier formData string true "PKCE verifier"
+// @Param client_id formData string false "Идентификатор клиента, если не используется HTTP Basic"
+// @Param client_secret formData string false "Секрет клиента, если не используется HTTP Basic"
+// @Success 200 {object} dto.OAuthTokenResponse
+// @Failure 400 {object} dto.OAuthError "Недействительный код или неподдерживаемый тип гранта"
+// @Failure 401 {object} dto.OAuthError "Ошибка аутентификации клиента"
+// @Router /oauth/token [post]
+func Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	clientID, clientSecret, basic := c.Request.BasicAuth()
+	if basic {
+		// Идентификатор и секрет в HTTP Basic кодируются как form-urlencoded (RFC 6749, раздел 2.3.1)
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = c.PostForm("client_id")
+		clientSecret = c.PostForm("client_secret")
+	}
+
+	if grantType := c.PostForm("grant_type"); grantType != "authorization_code" {
+		c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "unsupported_grant_type", ErrorDescription: "Поддерживается только authorization_code"})
+		return
+	}
+
+	client, err := services.AuthenticateOAuthClient(clientID, clientSecret)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidClient) {
+			utils.Log.Warnf("Ошибка аутентификации клиента %s на token endpoint", clientID)
+			if basic {
+				c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+			}
+			c.JSON(http.StatusUnauthorized, dto.OAuthError{Error: "invalid_client"})
+			return
+		}
+		utils.Log.Errorf("Ошибка при аутентификации клиента %s: %v", clientID, err)
+		c.JSON(http.StatusInternalServerError, dto.OAuthError{Error: "server_error"})
+		return
+	}
+
+	tokens, err := services.ExchangeAuthorizationCode(client, c.PostForm("code"), c.PostForm("redirect_uri"), c.PostForm("code_verifier"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidGrant) {
+			utils.Log.Warnf("Клиент %s передал недействительный код авторизации", client.ClientID)
+			c.JSON(http.StatusBadRequest, dto.OAuthError{Error: "invalid_grant"})
+			return
+		}
+		utils.Log.Errorf("Ошибка при обмене кода авторизации клиентом %s: %v", client.ClientID, err)
+		c.JSON(http.StatusInternalServerError, dto.OAuthError{Error: "server_error"})
+		return
+	}
+
+	utils.Log.Infof("Клиент %s получил токены по коду авторизации", client.ClientID)
+	c.JSON(http.StatusOK, dto.OAuthTokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   tokens.ExpiresIn,
+		IDToken:     tokens.IDToken,
+		Scope:       tokens.Scope,
+	})
+}
+
+// UserInfo godoc
+// @Summary Данные пользователя для клиента OpenID Connect
+// @Description Возвращает claims пользователя по access-токену, выданному клиенту через /oauth/token.
+// @Tags OIDC
+// @Produce json
+// @Param Authorization header string true "Bearer <access_token>"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.OAuthError "Недействительный access-токен"
+// @Router /oauth/userinfo [get]
+func UserInfo(c *gin.Context) {
+	raw := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+	access, err := services.ParseOAuthAccessToken(raw)
+	if err != nil {
+		respondInvalidOAuthToken(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, services.UserInfoClaims(access.User, access.Scope))
+}
+
+func respondInvalidOAuthToken(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	c.JSON(http.StatusUnauthorized, dto.OAuthError{Error: "invalid_token"})
+}
+
+// GetOpenIDConfiguration godoc
+// @Summary Discovery-документ OpenID Connect
+// @Description Доступен, только если токены подписываются асимметричными ключами из JWT_KEYS_DIR.
+// @Tags OIDC
+// @Produce json
+// @Success 200 {object} dto.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [get]
+func GetOpenIDConfiguration(c *gin.Context) {
+	issuer := config.OIDCIssuer
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, dto.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserinfoEndpoint:                  issuer + "/oauth/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  idTokenSigningAlgs(),
+		ScopesSupported:                   services.SupportedScopes,
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "email", "email_verified", "role", "updated_at"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{services.PKCEMethodS256},
+	})
+}
+
+// idTokenSigningAlgs возвращает асимметричные алгоритмы подписи ID-токенов.
+// Общий секрет HS256 никогда не объявляется: им подписываются и access-токены сервиса.
+func idTokenSigningAlgs() []string {
+	var algs []string
+	for _, alg := range config.Keys.Methods() {
+		if !strings.HasPrefix(alg, "HS") {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
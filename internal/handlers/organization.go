@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrganizations godoc
+// @Summary Список организаций
+// @Description Доступен администраторам организации по умолчанию.
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Organization
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав"
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении организаций"
+// @Router /organizations [get]
+func GetOrganizations(c *gin.Context) {
+	var organizations []models.Organization
+	if err := config.DB.Order("id").Find(&organizations).Error; err != nil {
+		utils.Log.Errorf("Ошибка при получении организаций: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить организации"})
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+}
+
+// CreateOrganization godoc
+// @Summary Создание организации
+// @Description Создает организацию со встроенными ролями admin, moderator и user и ее первого администратора.
+// @Description Администратор входит, указав slug организации в поле organization, после подтверждения email.
+// @Description Самостоятельная регистрация в организации закрыта, если не передан registration_open.
+// @Tags Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateOrganizationInput true "Организация и ее администратор"
+// @Success 201 {object} dto.OrganizationCreatedResponse
+// @Failure 400 {object} dto.ResponseError "Ошибка валидации или пароль не соответствует требованиям"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав"
+// @Failure 409 {object} dto.ResponseError "Slug уже занят"
+// @Failure 500 {object} dto.ResponseError "Ошибка при создании организации"
+// @Router /organizations [post]
+func CreateOrganization(c *gin.Context) {
+	var input dto.CreateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при создании организации: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	organization, admin, err := services.CreateOrganization(input.Name, input.Slug, input.RegistrationOpen, input.AdminName, input.AdminEmail, input.AdminPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrganizationSlugTaken):
+			c.JSON(http.StatusConflict, dto.ResponseError{Message: err.Error()})
+		case errors.Is(err, services.ErrInvalidOrganizationSlug), errors.Is(err, services.ErrPasswordPolicy):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		default:
+			utils.Log.Errorf("Не удалось создать организацию %s: %v", input.Slug, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось создать организацию"})
+		}
+		return
+	}
+
+	logAudit(c, models.ActionOrganizationCreated, models.TargetOrganization, organization.ID, models.AuditChanges{}.
+		Add("name", nil, organization.Name).
+		Add("slug", nil, organization.Slug).
+		Add("registration_open", nil, organization.RegistrationOpen).
+		Add("admin_email", nil, admin.Email))
+
+	if err := services.SendVerificationEmail(admin); err != nil {
+		utils.Log.Errorf("Не удалось отправить письмо для подтверждения email %s: %v", admin.Email, err)
+	}
+
+	utils.Log.Infof("Создана организация %s", organization.Slug)
+	c.JSON(http.StatusCreated, dto.OrganizationCreatedResponse{Organization: organization, Admin: admin})
+}
+
+// UpdateOrganization godoc
+// @Summary Изменение настроек организации
+// @Description Открывает или закрывает самостоятельную регистрацию пользователей в организации.
+// @Tags Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID организации"
+// @Param input body dto.UpdateOrganizationInput true "Настройки организации"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} dto.ResponseError "Ошибка валидации"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав"
+// @Failure 404 {object} dto.ResponseError "Организация не найдена"
+// @Failure 500 {object} dto.ResponseError "Ошибка при сохранении организации"
+// @Router /organizations/{id} [patch]
+func UpdateOrganization(c *gin.Context) {
+	var input dto.UpdateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при изменении организации: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	var organization models.Organization
+	if err := config.DB.First(&organization, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Организация с ID %s не найдена", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Организация не найдена"})
+		return
+	}
+
+	changes := models.AuditChanges{}.Add("registration_open", organization.RegistrationOpen, *input.RegistrationOpen)
+	if err := config.DB.Model(&organization).Update("registration_open", *input.RegistrationOpen).Error; err != nil {
+		utils.Log.Errorf("Не удалось изменить организацию %s: %v", organization.Slug, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось сохранить организацию"})
+		return
+	}
+
+	if len(changes) > 0 {
+		logAudit(c, models.ActionOrganizationUpdated, models.TargetOrganization, organization.ID, changes)
+	}
+
+	utils.Log.Infof("Организация %s изменена", organization.Slug)
+	c.JSON(http.StatusOK, organization)
+}
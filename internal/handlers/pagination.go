@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"strings"
+	"userManagement/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+// paginate подсчитывает общее количество записей запроса и возвращает запрос,
+// ограниченный текущей страницей
+func paginate(query *gorm.DB, q dto.PaginationQuery) (*gorm.DB, int64, error) {
+	// Сессия позволяет переиспользовать условия запроса для подсчета и выборки
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return query.Offset(q.Offset()).Limit(q.PageSize), total, nil
+}
+
+// likePattern экранирует спецсимволы LIKE и оборачивает строку для поиска подстроки
+func likePattern(search string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(search) + "%"
+}
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateRole godoc
+// @Summary Создание роли
+// @Description Создание пользовательской роли с указанным набором прав.
+// @Tags Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.RoleInput true "Параметры роли"
+// @Success 201 {object} models.Role
+// @Failure 400 {object} dto.ResponseError "Ошибка при создании роли"
+// @Failure 403 {object} dto.ResponseError "Недостаточно прав"
+// @Router /roles [post]
+func CreateRole(c *gin.Context) {
+	var input dto.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Некорректный ввод при создании роли: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	permissions, err := findPermissions(input.Permissions)
+	if err != nil {
+		utils.Log.Warnf("Некорректный набор прав при создании роли: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	role := models.Role{
+		OrganizationID: c.GetUint("organizationID"),
+		Name:           input.Name,
+		Description:    input.Description,
+		Require2FA:     input.Require2FA,
+		Permissions:    permissions,
+	}
+
+	if err := config.DB.Create(&role).Error; err != nil {
+		utils.Log.Errorf("Не удалось создать роль %s: %v", input.Name, err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Не удалось создать роль"})
+		return
+	}
+
+	logAudit(c, models.ActionRoleCreated, models.TargetRole, role.ID, models.AuditChanges{}.
+		Add("name", nil, role.Name).
+		Add("require_2fa", nil, role.Require2FA).
+		Add("permissions", nil, permissionNames(permissions)))
+
+	utils.Log.Infof("Создана роль %s", role.Name)
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRolePermissions godoc
+// @Summary Изменение прав роли
+// @Description Полностью заменяет набор прав роли. Права роли admin изменить нельзя.
+// @Tags Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID роли"
+// @Param input body dto.RolePermissionsInput true "Новый набор прав"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} dto.ResponseError "Неверный ввод"
+// @Failure 404 {object} dto.ResponseError "Роль не найдена"
+// @Router /roles/{id}/permissions [put]
+func UpdateRolePermissions(c *gin.Context) {
+	var role models.Role
+	if err := tenantDB(c).Preload("Permissions").First(&role, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Роль с ID %s не найдена", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Роль не найдена"})
+		return
+	}
+
+	// Админ всегда обладает всеми правами, иначе можно лишиться доступа к управлению ролями
+	if role.Name == models.RoleAdmin {
+		utils.Log.Warn("Попытка изменить права роли admin")
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Права роли admin изменить нельзя"})
+		return
+	}
+
+	var input dto.RolePermissionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Некорректный ввод при изменении прав роли: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	permissions, err := findPermissions(input.Permissions)
+	if err != nil {
+		utils.Log.Warnf("Некорректный набор прав для роли %s: %v", role.Name, err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	oldPermissions := permissionNames(role.Permissions)
+
+	association := config.DB.Model(&role).Association("Permissions")
+	if len(permissions) == 0 {
+		err = association.Clear()
+	} else {
+		err = association.Replace(&permissions)
+	}
+	if err != nil {
+		utils.Log.Errorf("Не удалось изменить права роли %s: %v", role.Name, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить права роли"})
+		return
+	}
+
+	logAudit(c, models.ActionRolePermissions, models.TargetRole, role.ID, models.AuditChanges{}.
+		Add("permissions", oldPermissions, permissionNames(permissions)))
+
+	config.DB.Preload("Permissions").First(&role, role.ID)
+
+	utils.Log.Infof("Права роли %s обновлены", role.Name)
+	c.JSON(http.StatusOK, role)
+}
+
+// GetRoles godoc
+// @Summary Получение списка ролей
+// @Tags Roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Role
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении списка ролей"
+// @Router /roles [get]
+func GetRoles(c *gin.Context) {
+	var roles []models.Role
+	if err := tenantDB(c).Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		utils.Log.Errorf("Ошибка при получении списка ролей: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении списка ролей"})
+		return
+	}
+
+	utils.Log.Info("Получен список ролей")
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetRole godoc
+// @Summary Получение роли по ID
+// @Tags Roles
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID роли"
+// @Success 200 {object} models.Role
+// @Failure 404 {object} dto.ResponseError "Роль не найдена"
+// @Router /roles/{id} [get]
+func GetRole(c *gin.Context) {
+	var role models.Role
+	if err := tenantDB(c).Preload("Permissions").First(&role, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Роль с ID %s не найдена", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Роль не найдена"})
+		return
+	}
+
+	utils.Log.Infof("Получена роль %s", role.Name)
+	c.JSON(http.StatusOK, role)
+}
+
+// UpdateRole godoc
+// @Summary Переименование роли и изменение описания
+// @Description Встроенные роли admin и user переименовать нельзя, но можно изменить их описание.
+// @Description Флаг require_2fa делает двухфакторную аутентификацию обязательной для пользователей роли.
+// @Tags Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID роли"
+// @Param input body dto.UpdateRoleInput true "Новое название и описание"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} dto.ResponseError "Неверный ввод"
+// @Failure 404 {object} dto.ResponseError "Роль не найдена"
+// @Router /roles/{id} [put]
+func UpdateRole(c *gin.Context) {
+	var role models.Role
+	if err := tenantDB(c).First(&role, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Роль с ID %s не найдена", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Роль не найдена"})
+		return
+	}
+
+	var input dto.UpdateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Некорректный ввод при обновлении роли: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	if role.IsProtected() && input.Name != role.Name {
+		utils.Log.Warnf("Попытка переименовать встроенную роль %s", role.Name)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Встроенную роль переименовать нельзя"})
+		return
+	}
+
+	changes := models.AuditChanges{}.
+		Add("name", role.Name, input.Name).
+		Add("description", role.Description, input.Description)
+
+	updates := map[string]interface{}{
+		"name":        input.Name,
+		"description": input.Description,
+	}
+	if input.Require2FA != nil {
+		changes.Add("require_2fa", role.Require2FA, *input.Require2FA)
+		updates["require_2fa"] = *input.Require2FA
+	}
+
+	oldName := role.Name
+	if err := config.DB.Model(&role).Updates(updates).Error; err != nil {
+		utils.Log.Errorf("Не удалось обновить роль %s: %v", oldName, err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Не удалось обновить роль"})
+		return
+	}
+
+	logAudit(c, models.ActionRoleUpdated, models.TargetRole, role.ID, changes)
+
+	config.DB.Preload("Permissions").First(&role, role.ID)
+
+	utils.Log.Infof("Роль %s обновлена", role.Name)
+	c.JSON(http.StatusOK, role)
+}
+
+// DeleteRole godoc
+// @Summary Удаление роли
+// @Description Удаляет роль. Если у роли есть пользователи, необходимо указать роль, на которую они будут переназначены.
+// @Description Встроенные роли admin и user удалить нельзя.
+// @Tags Roles
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID роли"
+// @Param reassign_to query string false "Название роли для переназначения пользователей"
+// @Success 200 {object} dto.ResponseMessage "Роль удалена"
+// @Failure 400 {object} dto.ResponseError "Неверный ввод"
+// @Failure 404 {object} dto.ResponseError "Роль не найдена"
+// @Failure 409 {object} dto.ResponseError "У роли есть пользователи"
+// @Router /roles/{id} [delete]
+func DeleteRole(c *gin.Context) {
+	var role models.Role
+	if err := tenantDB(c).First(&role, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Попытка удалить несуществующую роль с ID %s", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Роль не найдена"})
+		return
+	}
+
+	if role.IsProtected() {
+		utils.Log.Warnf("Попытка удалить встроенную роль %s", role.Name)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Встроенную роль удалить нельзя"})
+		return
+	}
+
+	var usersCount int64
+	if err := config.DB.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&usersCount).Error; err != nil {
+		utils.Log.Errorf("Ошибка при подсчете пользователей роли %s: %v", role.Name, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить роль"})
+		return
+	}
+
+	// Пользователей удаляемой роли нужно куда-то переназначить, иначе они останутся без роли
+	var target models.Role
+	reassignTo := c.Query("reassign_to")
+	if usersCount > 0 {
+		if reassignTo == "" {
+			utils.Log.Warnf("Попытка удалить роль %s, у которой есть пользователи", role.Name)
+			c.JSON(http.StatusConflict, dto.ResponseError{
+				Message: fmt.Sprintf("У роли есть пользователи (%d), укажите роль для переназначения в параметре reassign_to", usersCount),
+			})
+			return
+		}
+		if err := tenantDB(c).Where("name = ?", reassignTo).First(&target).Error; err != nil || target.ID == role.ID {
+			utils.Log.Warnf("Роль для переназначения %s не найдена", reassignTo)
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Роль для переназначения не найдена"})
+			return
+		}
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if usersCount > 0 {
+			if err := tx.Model(&models.User{}).Where("role_id = ?", role.ID).Update("role_id", target.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&role).Error
+	})
+	if err != nil {
+		utils.Log.Errorf("Не удалось удалить роль %s: %v", role.Name, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось удалить роль"})
+		return
+	}
+
+	changes := models.AuditChanges{}.Add("name", role.Name, nil)
+	if usersCount > 0 {
+		changes.Add("reassigned_users", nil, usersCount).Add("reassigned_to", nil, target.Name)
+	}
+	logAudit(c, models.ActionRoleDeleted, models.TargetRole, role.ID, changes)
+
+	utils.Log.Infof("Роль %s удалена", role.Name)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Роль удалена"})
+}
+
+// GetPermissions godoc
+// @Summary Получение списка прав
+// @Tags Roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Permission
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении списка прав"
+// @Router /permissions [get]
+func GetPermissions(c *gin.Context) {
+	var permissions []models.Permission
+	if err := config.DB.Order("name").Find(&permissions).Error; err != nil {
+		utils.Log.Errorf("Ошибка при получении списка прав: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении списка прав"})
+		return
+	}
+
+	utils.Log.Info("Получен список прав")
+	c.JSON(http.StatusOK, permissions)
+}
+
+// permissionNames возвращает коды прав в виде списка строк
+func permissionNames(permissions []models.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// findPermissions загружает права по их кодам и сообщает об отсутствующих
+func findPermissions(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := config.DB.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("Неизвестное право: %s", name)
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetMySessions godoc
+// @Summary Список сессий текущего пользователя
+// @Description Возвращает действующие входы пользователя: IP, User-Agent, время входа и последней активности.
+// @Description Сессия, из которой выполнен запрос, отмечена полем current.
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.SessionInfo
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Запрос выполнен по API-ключу"
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении сессий"
+// @Router /users/me/sessions [get]
+func GetMySessions(c *gin.Context) {
+	respondSessions(c, c.GetUint("userID"))
+}
+
+// RevokeMySession godoc
+// @Summary Завершение сессии текущего пользователя
+// @Description Отзывает refresh-токены и access-токен выбранного входа. Остальные сессии продолжают действовать.
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param sessionId path int true "ID сессии"
+// @Success 200 {object} dto.ResponseMessage "Сессия завершена"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Запрос выполнен по API-ключу"
+// @Failure 404 {object} dto.ResponseError "Сессия не найдена"
+// @Failure 500 {object} dto.ResponseError "Ошибка при завершении сессии"
+// @Router /users/me/sessions/{sessionId} [delete]
+func RevokeMySession(c *gin.Context) {
+	revokeSession(c, c.GetUint("userID"))
+}
+
+// GetUserSessions godoc
+// @Summary Список сессий пользователя
+// @Description Возвращает действующие входы пользователя по его ID.
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {array} dto.SessionInfo
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав"
+// @Failure 404 {object} dto.ResponseError "Пользователь не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении сессий"
+// @Router /users/{id}/sessions [get]
+func GetUserSessions(c *gin.Context) {
+	var user models.User
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для просмотра сессий", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	respondSessions(c, user.ID)
+}
+
+// RevokeUserSession godoc
+// @Summary Завершение сессии пользователя
+// @Description Завершает один вход пользователя по его ID. Для завершения всех сессий используется DELETE /users/{id}/sessions.
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param sessionId path int true "ID сессии"
+// @Success 200 {object} dto.ResponseMessage "Сессия завершена"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Нет прав"
+// @Failure 404 {object} dto.ResponseError "Пользователь или сессия не найдены"
+// @Failure 500 {object} dto.ResponseError "Ошибка при завершении сессии"
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func RevokeUserSession(c *gin.Context) {
+	var user models.User
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для завершения сессии", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	revokeSession(c, user.ID)
+}
+
+func respondSessions(c *gin.Context, userID uint) {
+	sessions, err := services.ListSessions(userID)
+	if err != nil {
+		utils.Log.Errorf("Не удалось получить сессии пользователя ID=%d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось получить сессии"})
+		return
+	}
+
+	currentID := c.GetUint("sessionID")
+	result := make([]dto.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.SessionInfo{
+			Session: session,
+			Current: currentID != 0 && session.ID == currentID && session.UserID == c.GetUint("userID"),
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func revokeSession(c *gin.Context, userID uint) {
+	sessionID, err := strconv.ParseUint(c.Param("sessionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Сессия не найдена"})
+		return
+	}
+
+	session, err := services.RevokeSession(userID, uint(sessionID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Сессия не найдена"})
+			return
+		}
+		utils.Log.Errorf("Не удалось завершить сессию ID=%d пользователя ID=%d: %v", sessionID, userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось завершить сессию"})
+		return
+	}
+
+	logAudit(c, models.ActionUserSessionRevoked, models.TargetUser, userID, models.AuditChanges{}.
+		Add("session_id", session.ID, nil).
+		Add("ip", session.IP, nil).
+		Add("user_agent", session.UserAgent, nil))
+
+	utils.Log.Infof("Сессия ID=%d пользователя ID=%d завершена", session.ID, userID)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Сессия завершена"})
+}
+
+// clientInfo возвращает данные устройства, которые сохраняются в сессии при входе
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
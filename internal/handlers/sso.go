@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/sso"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ssoStateCookie хранит токен состояния между переходом на провайдера и возвратом с него
+const ssoStateCookie = "sso_state"
+
+// GetSSOProviders godoc
+// @Summary Список провайдеров SSO
+// @Description Возвращает провайдеров, через которых можно войти, и адреса начала входа.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} dto.SSOProviderInfo
+// @Router /auth/sso [get]
+func GetSSOProviders(c *gin.Context) {
+	providers := make([]dto.SSOProviderInfo, 0, len(config.SSOProviderNames))
+	for _, name := range config.SSOProviderNames {
+		providers = append(providers, dto.SSOProviderInfo{
+			Name:        name,
+			DisplayName: config.SSOProviders[name].DisplayName,
+			LoginURL:    "/auth/sso/" + name + "/login",
+		})
+	}
+
+	c.JSON(http.StatusOK, providers)
+}
+
+// SSOLogin godoc
+// @Summary Начало входа через провайдера SSO
+// @Description Перенаправляет браузер на страницу входа провайдера (authorization code flow с PKCE).
+// @Description Состояние входа сохраняется в cookie до возврата на /auth/sso/{provider}/callback.
+// @Tags Auth
+// @Param provider path string true "Имя провайдера"
+// @Success 302 "Перенаправление на провайдера"
+// @Failure 404 {object} dto.ResponseError "Провайдер не найден"
+// @Failure 502 {object} dto.ResponseError "Провайдер недоступен"
+// @Router /auth/sso/{provider}/login [get]
+func SSOLogin(c *gin.Context) {
+	provider := c.Param("provider")
+
+	authURL, stateToken, err := services.BeginSSOLogin(c.Request.Context(), provider)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownSSOProvider) {
+			c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Провайдер не найден"})
+			return
+		}
+		utils.Log.Errorf("Не удалось начать вход через провайдера SSO %s: %v", provider, err)
+		c.JSON(http.StatusBadGateway, dto.ResponseError{Message: "Провайдер входа недоступен"})
+		return
+	}
+
+	setSSOStateCookie(c, stateToken, int(config.SSOStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// SSOCallback godoc
+// @Summary Завершение входа через провайдера SSO
+// @Description Принимает код авторизации от провайдера, находит связанного пользователя или создает нового
+// @Description и синхронизирует его роль и группы с группами провайдера. Дальше вход проходит как обычный:
+// @Description при включенной 2FA возвращается dto.TwoFactorChallengeResponse.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Param code query string false "Код авторизации"
+// @Param state query string false "Состояние запроса"
+// @Param error query string false "Ошибка, переданная провайдером"
+// @Success 200 {object} dto.AuthResponse "Access- и refresh-токены либо dto.TwoFactorChallengeResponse"
+// @Failure 401 {object} dto.ResponseError "Вход отклонен провайдером или истек"
+// @Failure 403 {object} dto.ResponseError "Учетная запись заблокирована или email не подтвержден"
+// @Failure 404 {object} dto.ResponseError "Провайдер не найден"
+// @Failure 409 {object} dto.ResponseError "Email занят учетной записью, не связанной с провайдером"
+// @Failure 502 {object} dto.ResponseError "Ошибка обращения к провайдеру"
+// @Router /auth/sso/{provider}/callback [get]
+func SSOCallback(c *gin.Context) {
+	provider := c.Param("provider")
+
+	var query dto.SSOCallbackQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	// Токен состояния одноразовый: cookie удаляется при любом исходе
+	stateToken, _ := c.Cookie(ssoStateCookie)
+	setSSOStateCookie(c, "", -1)
+
+	if query.Error != "" {
+		utils.Log.Warnf("Провайдер SSO %s отклонил вход: %s %s", provider, query.Error, query.ErrorDescription)
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Вход отклонен провайдером"})
+		return
+	}
+
+	result, err := services.CompleteSSOLogin(c.Request.Context(), provider, query.Code, query.State, stateToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownSSOProvider):
+			c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Провайдер не найден"})
+		case errors.Is(err, services.ErrUnknownOrganization):
+			utils.Log.Errorf("Организация провайдера SSO %s не найдена", provider)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось выполнить вход"})
+		case errors.Is(err, services.ErrInvalidSSOState):
+			utils.Log.Warnf("Возврат с провайдера SSO %s с недействительным состоянием", provider)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Запрос входа устарел, начните вход заново"})
+		case errors.Is(err, services.ErrSSOAccountConflict):
+			utils.Log.Warnf("Вход через провайдера SSO %s: email занят несвязанной учетной записью", provider)
+			c.JSON(http.StatusConflict, dto.ResponseError{Message: "Email уже используется другой учетной записью. Войдите по паролю"})
+		case errors.Is(err, services.ErrUserBanned):
+			utils.Log.Warnf("Попытка входа заблокированного пользователя через провайдера SSO %s: %s", provider, result.User.Email)
+			c.JSON(http.StatusForbidden, dto.BannedResponse{
+				Message:     "Учетная запись заблокирована",
+				Reason:      result.User.BanReason,
+				BannedUntil: result.User.BannedUntil,
+			})
+		case errors.Is(err, services.ErrSSOEmailMissing):
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Провайдер не передал email, вход невозможен"})
+		case errors.Is(err, sso.ErrInvalidIDToken), errors.Is(err, sso.ErrProvider):
+			utils.Log.Errorf("Ошибка входа через провайдера SSO %s: %v", provider, err)
+			c.JSON(http.StatusBadGateway, dto.ResponseError{Message: "Не удалось выполнить вход через провайдера"})
+		default:
+			utils.Log.Errorf("Ошибка входа через провайдера SSO %s: %v", provider, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось выполнить вход"})
+		}
+		return
+	}
+
+	setOrganization(c, result.User.OrganizationID)
+	logSSOChanges(c, result)
+
+	// Провайдер не подтвердил email: письмо отправляется так же, как при регистрации
+	if result.Created && !result.User.EmailVerified {
+		if err := services.SendVerificationEmail(result.User); err != nil {
+			utils.Log.Errorf("Не удалось отправить письмо для подтверждения email %s: %v", result.User.Email, err)
+		}
+	}
+
+	utils.Log.Infof("Пользователь %s прошел аутентификацию у провайдера SSO %s", result.User.Email, provider)
+	completeLogin(c, result.User)
+}
+
+// logSSOChanges записывает в журнал изменения, внесенные по данным провайдера.
+// Создание и связывание учетной записи выполняются от имени самого пользователя,
+// изменения роли и групп — от имени системы.
+func logSSOChanges(c *gin.Context, result services.SSOLoginResult) {
+	user := result.User
+	roleName := ""
+	if user.Role != nil {
+		roleName = user.Role.Name
+	}
+
+	if result.Created {
+		logAuditAs(c, user.ID, models.ActionUserCreated, models.TargetUser, user.ID, models.AuditChanges{}.
+			Add("name", nil, user.Name).
+			Add("email", nil, user.Email).
+			Add("role", nil, roleName).
+			Add("sso_provider", nil, result.Provider))
+	}
+	if result.Linked {
+		logAuditAs(c, user.ID, models.ActionUserSSOLinked, models.TargetUser, user.ID, models.AuditChanges{}.
+			Add("sso_provider", nil, result.Provider))
+	}
+
+	if result.OldRole != "" {
+		logAuditAs(c, 0, models.ActionUserRoleChanged, models.TargetUser, user.ID, models.AuditChanges{}.
+			Add("role", result.OldRole, roleName).
+			Add("sso_provider", nil, result.Provider))
+	}
+	for _, group := range result.GroupsAdded {
+		logAuditAs(c, 0, models.ActionGroupMemberAdded, models.TargetGroup, group.ID, models.AuditChanges{}.
+			Add("user_id", nil, user.ID).
+			Add("sso_provider", nil, result.Provider))
+	}
+	for _, group := range result.GroupsRemoved {
+		logAuditAs(c, 0, models.ActionGroupMemberRemoved, models.TargetGroup, group.ID, models.AuditChanges{}.
+			Add("user_id", user.ID, nil).
+			Add("sso_provider", nil, result.Provider))
+	}
+}
+
+// setSSOStateCookie сохраняет или, при отрицательном maxAge, удаляет cookie состояния входа.
+// SameSite=Lax нужен, чтобы cookie пришла при переходе с провайдера обратно на callback.
+func setSSOStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, value, maxAge, "/auth/sso", "", strings.HasPrefix(config.AppBaseURL, "https://"), true)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"userManagement/internal/config"
+	"userManagement/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// tenantDB возвращает запрос, ограниченный организацией текущего пользователя.
+// Все запросы к пользователям, группам, ролям и журналу из обработчиков строятся через него.
+func tenantDB(c *gin.Context) *gorm.DB {
+	return config.DB.Scopes(services.TenantScope(c.GetUint("organizationID")))
+}
+
+// setOrganization сохраняет организацию запроса без токена, когда она стала известна
+func setOrganization(c *gin.Context, organizationID uint) {
+	c.Set("organizationID", organizationID)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTwoFactor godoc
+// @Summary Начало настройки двухфакторной аутентификации
+// @Description Создает новый секрет TOTP и возвращает его вместе с otpauth:// ссылкой для QR-кода.
+// @Description 2FA включается только после подтверждения кодом из приложения.
+// @Tags 2FA
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.TwoFactorSetupResponse
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 409 {object} dto.ResponseError "2FA уже включена"
+// @Failure 500 {object} dto.ResponseError "Ошибка при создании секрета"
+// @Router /users/me/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	secret, uri, err := services.BeginTwoFactorSetup(userID)
+	if err != nil {
+		respondTwoFactorError(c, userID, err)
+		return
+	}
+
+	utils.Log.Infof("Пользователь ID=%d начал настройку 2FA", userID)
+	c.JSON(http.StatusOK, dto.TwoFactorSetupResponse{Secret: secret, OTPAuthURL: uri})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Подтверждение и включение двухфакторной аутентификации
+// @Description Включает 2FA, если код из приложения совпал, и возвращает коды восстановления. Коды показываются один раз.
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorCodeInput true "Код из приложения"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ResponseError "Неверный код или настройка не начата"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 409 {object} dto.ResponseError "2FA уже включена"
+// @Router /users/me/2fa/confirm [post]
+func ConfirmTwoFactor(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при подтверждении 2FA: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	codes, err := services.ConfirmTwoFactor(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(c, userID, err)
+		return
+	}
+
+	logAudit(c, models.ActionUser2FAEnabled, models.TargetUser, userID, models.AuditChanges{}.
+		Add("two_factor_enabled", false, true))
+
+	utils.Log.Infof("Пользователь ID=%d включил 2FA", userID)
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Отключение двухфакторной аутентификации
+// @Description Отключает 2FA после проверки пароля и кода. Недоступно, если роль требует 2FA.
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorDisableInput true "Пароль и код из приложения или код восстановления"
+// @Success 200 {object} dto.ResponseMessage "2FA отключена"
+// @Failure 400 {object} dto.ResponseError "Неверный код или 2FA не включена"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Неверный пароль или 2FA обязательна для роли"
+// @Router /users/me/2fa/disable [post]
+func DisableTwoFactor(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.TwoFactorDisableInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при отключении 2FA: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	if err := services.DisableTwoFactor(userID, input.Password, input.Code); err != nil {
+		respondTwoFactorError(c, userID, err)
+		return
+	}
+
+	logAudit(c, models.ActionUser2FADisabled, models.TargetUser, userID, models.AuditChanges{}.
+		Add("two_factor_enabled", true, false))
+
+	utils.Log.Infof("Пользователь ID=%d отключил 2FA", userID)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Двухфакторная аутентификация отключена"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Новые коды восстановления
+// @Description Заменяет коды восстановления новыми. Старые коды перестают действовать.
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorCodeInput true "Код из приложения или код восстановления"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ResponseError "Неверный код или 2FA не включена"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Router /users/me/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при обновлении кодов восстановления: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	codes, err := services.RegenerateRecoveryCodes(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(c, userID, err)
+		return
+	}
+
+	logAudit(c, models.ActionUserRecoveryCodes, models.TargetUser, userID, nil)
+
+	utils.Log.Infof("Пользователь ID=%d получил новые коды восстановления", userID)
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// VerifyTwoFactor godoc
+// @Summary Второй шаг входа
+// @Description Принимает токен второго шага из /auth/login и код из приложения или код восстановления, выдает пару токенов.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorVerifyInput true "Токен второго шага и код"
+// @Success 200 {object} dto.AuthResponse "Access- и refresh-токены"
+// @Failure 400 {object} dto.ResponseError "Ошибка при валидации данных"
+// @Failure 401 {object} dto.ResponseError "Неверный код или недействительный токен второго шага"
+// @Failure 403 {object} dto.ResponseError "Учетная запись заблокирована или email не подтвержден"
+// @Failure 423 {object} dto.LockedResponse "Учетная запись временно заблокирована после неудачных попыток входа"
+// @Failure 429 {object} dto.ResponseError "Слишком много неудачных попыток входа с IP"
+// @Router /auth/2fa/verify [post]
+func VerifyTwoFactor(c *gin.Context) {
+	var input dto.TwoFactorVerifyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации на втором шаге входа: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	ip := c.ClientIP()
+	if services.IsIPLoginBlocked(ip) {
+		utils.Log.Warnf("Второй шаг входа с IP %s временно запрещен из-за неудачных попыток", ip)
+		c.JSON(http.StatusTooManyRequests, dto.ResponseError{Message: "Слишком много неудачных попыток входа. Попробуйте позже"})
+		return
+	}
+
+	tokens, user, usedRecovery, err := services.CompleteTwoFactorLogin(input.ChallengeToken, input.Code, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccountLocked):
+			// Блокировка наступила из-за этого неверного кода — фиксируем ее так же, как после неверного пароля
+			if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+				setOrganization(c, user.OrganizationID)
+				logAuditAs(c, 0, models.ActionAuthAccountLocked, models.TargetUser, user.ID, models.AuditChanges{}.
+					Add("locked_until", nil, user.LockedUntil).
+					Add("lockout_count", user.LockoutCount-1, user.LockoutCount))
+				utils.Log.Warnf("Учетная запись %s заблокирована до %s после неверных кодов 2FA", user.Email, user.LockedUntil.Format(time.RFC3339))
+			}
+			c.JSON(http.StatusLocked, dto.LockedResponse{
+				Message:     "Учетная запись временно заблокирована из-за неудачных попыток входа",
+				LockedUntil: *user.LockedUntil,
+			})
+		case errors.Is(err, services.ErrInvalidChallenge):
+			utils.Log.Warn("Попытка входа с недействительным токеном второго шага")
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Токен второго шага недействителен или устарел, войдите заново"})
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			utils.Log.Warnf("Неверный код 2FA при входе пользователя ID=%d", user.ID)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Неверный код"})
+		case errors.Is(err, services.ErrUserBanned):
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Учетная запись заблокирована"})
+		case errors.Is(err, services.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Email не подтвержден"})
+		default:
+			utils.Log.Errorf("Ошибка на втором шаге входа: %v", err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка создания токена"})
+		}
+		return
+	}
+
+	// Использование кода восстановления означает, что доступа к приложению может не быть — фиксируем это
+	if usedRecovery {
+		setOrganization(c, user.OrganizationID)
+		logAuditAs(c, user.ID, models.ActionAuthRecoveryCode, models.TargetUser, user.ID, nil)
+	}
+
+	utils.Log.Infof("Пользователь %s успешно вошел в систему с 2FA", user.Email)
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+// respondTwoFactorError переводит ошибки настройки 2FA в HTTP-ответы
+func respondTwoFactorError(c *gin.Context, userID uint, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		c.JSON(http.StatusConflict, dto.ResponseError{Message: "Двухфакторная аутентификация уже включена"})
+	case errors.Is(err, services.ErrTwoFactorNotEnabled):
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Двухфакторная аутентификация не включена"})
+	case errors.Is(err, services.ErrTwoFactorNotSetUp):
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Сначала начните настройку через /users/me/2fa/setup"})
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		utils.Log.Warnf("Неверный код 2FA от пользователя ID=%d", userID)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Неверный код"})
+	case errors.Is(err, services.ErrWrongCurrentPassword):
+		utils.Log.Warnf("Неверный пароль при отключении 2FA пользователем ID=%d", userID)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Неверный пароль"})
+	case errors.Is(err, services.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Для вашей роли двухфакторная аутентификация обязательна"})
+	default:
+		utils.Log.Errorf("Ошибка при работе с 2FA пользователя ID=%d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка двухфакторной аутентификации"})
+	}
+}
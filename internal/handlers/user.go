@@ -1,8 +1,11 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 	"userManagement/internal/config"
 	"userManagement/internal/dto"
 	"userManagement/internal/models"
@@ -33,6 +36,12 @@ func CreateUser(c *gin.Context) {
 
 	input.Sanitize()
 
+	if err := services.ValidatePassword(input.Password, input.Email, input.Name); err != nil {
+		utils.Log.Warnf("Пароль не соответствует политике при создании пользователя %s: %v", input.Email, err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
 	// Хешируем пароль
 	hashedPassword, errPassword := utils.HashPassword(input.Password)
 	if errPassword != nil {
@@ -41,11 +50,20 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	organizationID := c.GetUint("organizationID")
+	role, err := services.GetDefaultRole(organizationID)
+	if err != nil {
+		utils.Log.Errorf("Не удалось получить роль по умолчанию: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось создать пользователя"})
+		return
+	}
+
 	user := models.User{
-		Name:         input.Name,
-		Email:        input.Email,
-		PasswordHash: hashedPassword,
-		RoleID:       3,
+		OrganizationID: organizationID,
+		Name:           input.Name,
+		Email:          input.Email,
+		PasswordHash:   hashedPassword,
+		RoleID:         role.ID,
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
@@ -57,12 +75,13 @@ func CreateUser(c *gin.Context) {
 	// Загружаем роль для возвращаемого пользователя
 	config.DB.Preload("Role").First(&user, user.ID)
 
-	if currentUser, exists := c.Get("currentUser"); exists {
-		userInfo := currentUser.(dto.UserInfo)
-		err := services.LogAction(userInfo.ID, fmt.Sprintf("Создал пользователя: %s", user.Name))
-		if err != nil {
-			utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-		}
+	logAudit(c, models.ActionUserCreated, models.TargetUser, user.ID, models.AuditChanges{}.
+		Add("name", nil, user.Name).
+		Add("email", nil, user.Email).
+		Add("role", nil, role.Name))
+
+	if err := services.SendVerificationEmail(user); err != nil {
+		utils.Log.Errorf("Не удалось отправить письмо для подтверждения email %s: %v", user.Email, err)
 	}
 
 	utils.Log.Errorf("Пользователь %s успешно создан", user.Name)
@@ -71,12 +90,22 @@ func CreateUser(c *gin.Context) {
 
 // GetUsers godoc
 // @Summary Получение списка пользователей
-// @Description Получение списка всех пользователей или отфильтрованных по роли.
+// @Description Постраничный список пользователей с поиском по имени и email, фильтрами и сортировкой.
 // @Tags Users
 // @Security BearerAuth
 // @Produce  json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не более 100)" default(20)
 // @Param role query string false "Роль пользователя"
-// @Success 200 {array} models.User
+// @Param search query string false "Поиск по имени и email"
+// @Param is_banned query bool false "Фильтр по блокировке"
+// @Param group_id query int false "ID группы, в которой состоит пользователь"
+// @Param created_from query string false "Создан не раньше (RFC3339)"
+// @Param created_to query string false "Создан не позже (RFC3339)"
+// @Param sort_by query string false "Поле сортировки" Enums(name, email, created_at)
+// @Param order query string false "Направление сортировки" Enums(asc, desc)
+// @Success 200 {object} dto.PageResponse{data=[]models.User}
+// @Failure 400 {object} dto.ResponseError "Некорректные параметры запроса"
 // @Failure 500 {object} dto.ResponseError "Ошибка при получении списка пользователей"
 // @Router /users [get]
 func GetUsers(c *gin.Context) {
@@ -87,30 +116,100 @@ func GetUsers(c *gin.Context) {
 		return
 	}
 
-	roleName := c.Query("role")
+	var params dto.UserListQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Log.Warnf("Некорректные параметры списка пользователей: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+	params.Normalize()
+
 	var users []models.User
 
-	query := config.DB
-	if roleName != "" {
+	query := tenantDB(c).Model(&models.User{})
+	if params.Role != "" {
 		// Используем внешний ключ RoleID, а не строку
 		var role models.Role
-		if err := config.DB.Where("name = ?", roleName).First(&role).Error; err == nil {
+		if err := tenantDB(c).Where("name = ?", params.Role).First(&role).Error; err == nil {
 			query = query.Where("role_id = ?", role.ID)
 		} else {
-			utils.Log.Warnf("Роль %s не найдена при фильтрации", roleName)
+			utils.Log.Warnf("Роль %s не найдена при фильтрации", params.Role)
 			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Роль не найдена"})
 			return
 		}
 	}
+	if search := strings.TrimSpace(params.Search); search != "" {
+		pattern := likePattern(search)
+		query = query.Where("name ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if params.IsBanned != nil {
+		query = query.Where("is_banned = ?", *params.IsBanned)
+	}
+	if params.GroupID != 0 {
+		query = query.Where("id IN (?)", config.DB.Table("group_users").Select("user_id").Where("group_id = ?", params.GroupID))
+	}
+	if params.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *params.CreatedFrom)
+	}
+	if params.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *params.CreatedTo)
+	}
 
-	if err := query.Preload("Role").Find(&users).Error; err != nil {
+	sortBy := params.SortBy
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	order := params.Order
+	if order == "" {
+		order = "asc"
+	}
+
+	page, total, err := paginate(query, params.PaginationQuery)
+	if err != nil {
+		utils.Log.Errorf("Ошибка при подсчете пользователей: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении списка пользователей"})
+		return
+	}
+
+	// Сортировка по id делает порядок стабильным при совпадающих значениях
+	if err := page.Order(sortBy + " " + order).Order("id " + order).Preload("Role").Find(&users).Error; err != nil {
 		utils.Log.Errorf("Ошибка при получении пользователей: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении списка пользователей"})
 		return
 	}
 
 	utils.Log.Info("Получен список пользователей")
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, dto.NewPageResponse(users, params.PaginationQuery, total))
+}
+
+// GetUser godoc
+// @Summary Получение пользователя по ID
+// @Description Возвращает пользователя с ролью, а также причину и срок блокировки, если она действует.
+// @Tags Users
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.User
+// @Failure 404 {object} dto.ResponseError "Пользователь не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при получении пользователя"
+// @Router /users/{id} [get]
+func GetUser(c *gin.Context) {
+	var user models.User
+	if err := tenantDB(c).Preload("Role").First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	// Снимаем истекшую временную блокировку, чтобы не показывать устаревшие данные
+	if err := services.CheckBan(config.DB, &user); err != nil && !errors.Is(err, services.ErrUserBanned) {
+		utils.Log.Errorf("Ошибка при проверке блокировки пользователя %s: %v", user.Email, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка при получении пользователя"})
+		return
+	}
+
+	utils.Log.Infof("Получен пользователь %s", user.Email)
+	c.JSON(http.StatusOK, user)
 }
 
 // UpdateUser godoc
@@ -140,15 +239,15 @@ func UpdateUser(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
-		utils.Log.Warnf("Пользователь с ID %d не найден для обновления", c.Param("id"))
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для обновления", c.Param("id"))
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
 	// Проверка прав доступа:
-	// Если текущий пользователь не является администратором или модератором, он не может редактировать чужие данные
-	if currentUser.ID != user.ID && currentUser.Role.Name != "admin" && currentUser.Role.Name != "moderator" {
+	// Если у текущего пользователя нет права на редактирование пользователей, он не может редактировать чужие данные
+	if currentUser.ID != user.ID && !userInfo.HasPermission(models.PermUsersUpdate) {
 		utils.Log.Warnf("Пользователь %d пытался обновить чужие данные", currentUser.ID)
 		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Недостаточно прав для реадктирования других пользователей"})
 		return
@@ -163,23 +262,38 @@ func UpdateUser(c *gin.Context) {
 
 	input.Sanitize()
 
+	changes := models.AuditChanges{}.Add("name", user.Name, input.Name)
+	emailChanged := input.Email != "" && input.Email != user.Email
+	if emailChanged {
+		changes.Add("email", user.Email, input.Email)
+		changes.Add("email_verified", user.EmailVerified, false)
+	}
+
+	updates := map[string]interface{}{"name": input.Name}
+	if emailChanged {
+		// Новый адрес нужно подтвердить заново
+		updates["email"] = input.Email
+		updates["email_verified"] = false
+		updates["email_verified_at"] = nil
+	}
+
 	// Обновляем данные пользователя
-	if err := config.DB.Model(&user).Updates(models.User{
-		Name:  input.Name,
-		Email: input.Email,
-	}).Error; err != nil {
+	if err := config.DB.Model(&user).Updates(updates).Error; err != nil {
 		utils.Log.Errorf("Не удалось обновить пользователя: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось обновить данные пользователя"})
 		return
 	}
 
-	err := services.LogAction(currentUser.ID, fmt.Sprintf("Обновил пользователя: %s", user.Name))
-	if err != nil {
-		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-	}
+	logAudit(c, models.ActionUserUpdated, models.TargetUser, user.ID, changes)
 
 	// Подгружаем роль перед возвратом
 	config.DB.Preload("Role").First(&user, user.ID)
+
+	if emailChanged {
+		if err := services.SendVerificationEmail(user); err != nil {
+			utils.Log.Errorf("Не удалось отправить письмо для подтверждения email %s: %v", user.Email, err)
+		}
+	}
 	utils.Log.Infof("Пользователь %s обновлен", user.Email)
 	c.JSON(http.StatusOK, user)
 }
@@ -194,31 +308,25 @@ func UpdateUser(c *gin.Context) {
 // @Failure 404 {object} dto.ResponseError "Пользователь не найден"
 // @Router /users/{id} [delete]
 func DeleteUser(c *gin.Context) {
-	currentUserRaw, exists := c.Get("currentUser")
+	_, exists := c.Get("currentUser")
 	if !exists {
 		utils.Log.Warn("Попытка неавторизованного удаления пользователя")
 		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Необходима авторизация"})
 		return
 	}
-	userInfo := currentUserRaw.(dto.UserInfo)
-	currentUser := models.User{
-		ID:   userInfo.ID,
-		Role: userInfo.Role,
-	}
 
 	var user models.User
-	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
-		utils.Log.Warnf("Пользователь с ID %d не найден для удаления", c.Param("id"))
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для удаления", c.Param("id"))
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
 	config.DB.Unscoped().Delete(&user)
 
-	err := services.LogAction(currentUser.ID, fmt.Sprintf("Удалил пользователя: %s", user.Name))
-	if err != nil {
-		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-	}
+	logAudit(c, models.ActionUserDeleted, models.TargetUser, user.ID, models.AuditChanges{}.
+		Add("name", user.Name, nil).
+		Add("email", user.Email, nil))
 
 	utils.Log.Infof("Пользователь %s удален", user.Email)
 	c.JSON(http.StatusOK, dto.ResponseError{Message: "Пользователь удален"})
@@ -247,7 +355,7 @@ func UpdateUserRole(c *gin.Context) {
 	userInfo := currentUserRaw.(dto.UserInfo)
 
 	var user models.User
-	if err := config.DB.Preload("Role").First(&user, c.Param("id")).Error; err != nil {
+	if err := tenantDB(c).Preload("Role").First(&user, c.Param("id")).Error; err != nil {
 		utils.Log.Warnf("Пользователь с ID %d не найден для обновления роли", userInfo.ID)
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
@@ -262,7 +370,7 @@ func UpdateUserRole(c *gin.Context) {
 
 	// Найти роль по имени
 	var role models.Role
-	if err := config.DB.Where("name = ?", input.RoleName).First(&role).Error; err != nil {
+	if err := tenantDB(c).Where("name = ?", input.RoleName).First(&role).Error; err != nil {
 		utils.Log.Warnf("Роль с именем %s не найдена", input.RoleName)
 		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Указанная роль не найдена"})
 		return
@@ -278,10 +386,8 @@ func UpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	err := services.LogAction(userInfo.ID, fmt.Sprintf("Обновил роль пользователя %s: %s -> %s", user.Name, oldRole, user.Role.Name))
-	if err != nil {
-		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-	}
+	logAudit(c, models.ActionUserRoleChanged, models.TargetUser, user.ID, models.AuditChanges{}.
+		Add("role", oldRole, user.Role.Name))
 
 	// Подгружаем новую роль перед возвратом
 	config.DB.Preload("Role").First(&user, user.ID)
@@ -291,54 +397,69 @@ func UpdateUserRole(c *gin.Context) {
 }
 
 // BanUser godoc
-// @Summary Временная блокировка пользователя
+// @Summary Блокировка пользователя
+// @Description Блокирует пользователя с необязательной причиной. Если указан срок, блокировка снимается автоматически.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path int true "ID пользователя"
+// @Param input body dto.BanUserInput false "Причина и срок блокировки"
 // @Success 200 {object} dto.ResponseMessage
 // @Failure 400 {object} dto.ResponseError
 // @Failure 403 {object} dto.ResponseError
 // @Router /users/{id}/ban [patch]
 // @Security BearerAuth
 func BanUser(c *gin.Context) {
-	currentUserRaw, exists := c.Get("currentUser")
+	_, exists := c.Get("currentUser")
 	if !exists {
 		utils.Log.Warnf("Попытка неавторизованного блокирования пользователя")
 		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Необходима авторизация"})
 		return
 	}
-	userInfo := currentUserRaw.(dto.UserInfo)
-	currentUser := models.User{
-		ID:   userInfo.ID,
-		Role: userInfo.Role,
-	}
 
 	var user models.User
-	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
-		utils.Log.Warnf("Пользователь с ID %s не найден для блокировки", currentUser.ID)
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для блокировки", c.Param("id"))
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
+	var input dto.BanUserInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		utils.Log.Warnf("Некорректный ввод при блокировке пользователя: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	input.Sanitize()
+
+	now := time.Now()
+	if input.Until != nil && !input.Until.After(now) {
+		utils.Log.Warnf("Попытка блокировки пользователя %s со сроком в прошлом", user.Email)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Срок блокировки должен быть в будущем"})
+		return
+	}
+
 	// Проверяяем, что пользователь не заблокирован
-	if user.IsBanned {
+	if user.IsBanActive(now) {
 		utils.Log.Warnf("Попытка блокирования уже заблокированного пользователя")
 		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь уже заблокирован"})
 		return
 	}
 
 	user.IsBanned = true
+	user.BanReason = input.Reason
+	user.BannedUntil = input.Until
 	if err := config.DB.Save(&user).Error; err != nil {
 		utils.Log.Errorf("Не удалось заблокировать пользователя: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось заблокировать пользователя"})
 		return
 	}
 
-	err := services.LogAction(currentUser.ID, fmt.Sprintf("Заблокировал пользователя: %s", user.Name))
-	if err != nil {
-		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-	}
+	logAudit(c, models.ActionUserBanned, models.TargetUser, user.ID, models.AuditChanges{}.
+		Add("is_banned", false, true).
+		Add("ban_reason", "", user.BanReason).
+		Add("banned_until", nil, user.BannedUntil))
 
 	utils.Log.Infof("Пользователь %s заблокирован", user.Email)
 	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Пользователь заблокирован"})
@@ -356,33 +477,35 @@ func BanUser(c *gin.Context) {
 // @Router /users/{id}/unban [patch]
 // @Security BearerAuth
 func UnbanUser(c *gin.Context) {
-	currentUserRaw, exists := c.Get("currentUser")
+	_, exists := c.Get("currentUser")
 	if !exists {
 		utils.Log.Warnf("Попытка неавторизованного разблокирования пользователя")
 		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Необходима авторизация"})
 		return
 	}
-	userInfo := currentUserRaw.(dto.UserInfo)
-	currentUser := models.User{
-		ID:   userInfo.ID,
-		Role: userInfo.Role,
-	}
 
 	var user models.User
-	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
-		utils.Log.Warnf("Пользователь с ID %s не найден для разблокировки", currentUser.ID)
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для разблокировки", c.Param("id"))
 		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
 		return
 	}
 
 	// Проверяем, что пользователь не заблокирован
-	if !user.IsBanned {
+	if !user.IsBanActive(time.Now()) {
 		utils.Log.Warnf("Попытка разблокирования не заблокированного пользователя")
 		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Пользователь не заблокирован"})
 		return
 	}
 
+	changes := models.AuditChanges{}.
+		Add("is_banned", true, false).
+		Add("ban_reason", user.BanReason, "").
+		Add("banned_until", user.BannedUntil, nil)
+
 	user.IsBanned = false
+	user.BanReason = ""
+	user.BannedUntil = nil
 	if err := config.DB.Save(&user).Error; err != nil {
 		utils.Log.Errorf("Не удалось разблокировать пользователя: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось разблокировать пользователя"})
@@ -390,15 +513,54 @@ func UnbanUser(c *gin.Context) {
 	}
 
 	// Логируем действие
-	err := services.LogAction(currentUser.ID, fmt.Sprintf("Разблокировал пользователя: %s", user.Name))
-	if err != nil {
-		utils.Log.Errorf("Ошибка при логировании действия: %v", err)
-	}
+	logAudit(c, models.ActionUserUnbanned, models.TargetUser, user.ID, changes)
 
 	utils.Log.Infof("Пользователь %s разблокирован", user.Email)
 	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Пользователь разблокирован"})
 }
 
+// UnlockUser godoc
+// @Summary Досрочная разблокировка учетной записи
+// @Description Снимает временную блокировку после неудачных попыток входа и сбрасывает счетчики попыток.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} dto.ResponseMessage "Учетная запись разблокирована"
+// @Failure 400 {object} dto.ResponseError "Учетная запись не заблокирована"
+// @Failure 404 {object} dto.ResponseError "Пользователь не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при разблокировке"
+// @Router /users/{id}/unlock [patch]
+func UnlockUser(c *gin.Context) {
+	var user models.User
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для снятия блокировки входа", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	if !user.IsLocked(time.Now()) {
+		utils.Log.Warnf("Попытка снять блокировку входа с незаблокированного пользователя %s", user.Email)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: "Учетная запись не заблокирована"})
+		return
+	}
+
+	changes := models.AuditChanges{}.
+		Add("locked_until", user.LockedUntil, nil).
+		Add("lockout_count", user.LockoutCount, 0)
+
+	if err := services.ResetFailedLogins(&user); err != nil {
+		utils.Log.Errorf("Не удалось снять блокировку входа с пользователя %s: %v", user.Email, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось разблокировать учетную запись"})
+		return
+	}
+
+	logAudit(c, models.ActionUserUnlocked, models.TargetUser, user.ID, changes)
+
+	utils.Log.Infof("С учетной записи %s снята блокировка входа", user.Email)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Учетная запись разблокирована"})
+}
+
 // GetProfile godoc
 // @Summary Получение профиля текущего пользователя
 // @Tags Users
@@ -430,3 +592,95 @@ func GetProfile(c *gin.Context) {
 	utils.Log.Infof("Получен профиль пользователя %s", user.Email)
 	c.JSON(http.StatusOK, user)
 }
+
+// RevokeUserSessions godoc
+// @Summary Завершение всех сессий пользователя
+// @Description Отзывает все access- и refresh-токены пользователя по его ID.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} dto.ResponseMessage "Сессии пользователя завершены"
+// @Failure 404 {object} dto.ResponseError "Пользователь не найден"
+// @Failure 500 {object} dto.ResponseError "Ошибка при завершении сессий"
+// @Router /users/{id}/sessions [delete]
+func RevokeUserSessions(c *gin.Context) {
+	_, exists := c.Get("currentUser")
+	if !exists {
+		utils.Log.Warn("Попытка неавторизованного завершения сессий пользователя")
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Необходима авторизация"})
+		return
+	}
+
+	var user models.User
+	if err := tenantDB(c).First(&user, c.Param("id")).Error; err != nil {
+		utils.Log.Warnf("Пользователь с ID %s не найден для завершения сессий", c.Param("id"))
+		c.JSON(http.StatusNotFound, dto.ResponseError{Message: "Пользователь не найден"})
+		return
+	}
+
+	if err := services.RevokeAllUserSessions(user.ID); err != nil {
+		utils.Log.Errorf("Не удалось завершить сессии пользователя %s: %v", user.Email, err)
+		c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось завершить сессии пользователя"})
+		return
+	}
+
+	logAudit(c, models.ActionUserSessionsRevoked, models.TargetUser, user.ID, nil)
+
+	utils.Log.Infof("Сессии пользователя %s завершены", user.Email)
+	c.JSON(http.StatusOK, dto.ResponseMessage{Message: "Сессии пользователя завершены"})
+}
+
+// ChangePassword godoc
+// @Summary Смена пароля текущего пользователя
+// @Description Меняет пароль после проверки текущего. Все остальные сессии завершаются, в ответе возвращается новая пара токенов для текущей.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dto.ChangePasswordInput true "Текущий и новый пароль"
+// @Success 200 {object} dto.AuthResponse "Новые access- и refresh-токены"
+// @Failure 400 {object} dto.ResponseError "Ошибка валидации или пароль не соответствует требованиям"
+// @Failure 401 {object} dto.ResponseError "Неавторизованный доступ"
+// @Failure 403 {object} dto.ResponseError "Неверный текущий пароль"
+// @Failure 500 {object} dto.ResponseError "Ошибка при смене пароля"
+// @Router /users/me/password [patch]
+func ChangePassword(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input dto.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.Log.Warnf("Ошибка валидации при смене пароля: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		return
+	}
+
+	tokens, user, err := services.ChangePassword(userID, input.CurrentPassword, input.NewPassword, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWrongCurrentPassword):
+			utils.Log.Warnf("Неверный текущий пароль при смене пароля пользователем ID=%d", userID)
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Неверный текущий пароль"})
+		case errors.Is(err, services.ErrPasswordPolicy):
+			c.JSON(http.StatusBadRequest, dto.ResponseError{Message: err.Error()})
+		default:
+			utils.Log.Errorf("Ошибка при смене пароля пользователя ID=%d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось изменить пароль"})
+		}
+		return
+	}
+
+	// Текущий токен мог быть выдан в ту же секунду, что и отзыв, поэтому отзываем его явно
+	if err := services.RevokeToken(c.GetString("tokenID"), userID, c.GetTime("tokenExpiresAt")); err != nil {
+		utils.Log.Errorf("Ошибка при отзыве токена пользователя ID=%d: %v", userID, err)
+	}
+
+	logAudit(c, models.ActionUserPasswordChanged, models.TargetUser, userID, nil)
+
+	utils.Log.Infof("Пользователь %s сменил пароль", user.Email)
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
@@ -0,0 +1,131 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWK — открытый ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// Параметры RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Параметры Ed25519 и ECDSA
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet — набор открытых ключей для /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает открытые части всех ключей. В режиме HS256 набор пуст:
+// общий секрет публиковать нельзя.
+func (r *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, key := range r.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+		switch public := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// PublicKey разбирает открытый ключ и определяет алгоритм подписи.
+// Используется для проверки токенов внешних провайдеров, поэтому кроме RSA и Ed25519 поддерживается ECDSA.
+func (k JWK) PublicKey() (crypto.PublicKey, jwt.SigningMethod, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ключ %q: некорректный модуль RSA: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, nil, fmt.Errorf("ключ %q: некорректная экспонента RSA", k.Kid)
+		}
+
+		var method jwt.SigningMethod = jwt.SigningMethodRS256
+		switch k.Alg {
+		case "", "RS256":
+		case "RS384":
+			method = jwt.SigningMethodRS384
+		case "RS512":
+			method = jwt.SigningMethodRS512
+		case "PS256":
+			method = jwt.SigningMethodPS256
+		default:
+			return nil, nil, fmt.Errorf("ключ %q: неподдерживаемый алгоритм %s", k.Kid, k.Alg)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, method, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil, fmt.Errorf("ключ %q: неподдерживаемая кривая %s", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("ключ %q: некорректный ключ Ed25519", k.Kid)
+		}
+		return ed25519.PublicKey(x), jwt.SigningMethodEdDSA, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var method jwt.SigningMethod
+		switch k.Crv {
+		case "P-256":
+			curve, method = elliptic.P256(), jwt.SigningMethodES256
+		case "P-384":
+			curve, method = elliptic.P384(), jwt.SigningMethodES384
+		case "P-521":
+			curve, method = elliptic.P521(), jwt.SigningMethodES512
+		default:
+			return nil, nil, fmt.Errorf("ключ %q: неподдерживаемая кривая %s", k.Kid, k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, nil, fmt.Errorf("ключ %q: некорректные координаты ECDSA", k.Kid)
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(public.X, public.Y) {
+			return nil, nil, fmt.Errorf("ключ %q: точка не лежит на кривой %s", k.Kid, k.Crv)
+		}
+		return public, method, nil
+	}
+
+	return nil, nil, fmt.Errorf("ключ %q: неподдерживаемый тип %s", k.Kid, k.Kty)
+}
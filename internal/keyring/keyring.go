@@ -0,0 +1,193 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key — ключ подписи JWT. Ключ без закрытой части используется только для проверки подписи.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	PublicKey crypto.PublicKey
+}
+
+// Keyring хранит ключи подписи JWT: активный ключ подписывает новые токены,
+// остальные продолжают проверять ранее выданные, пока те не истекут.
+// Без ключей Keyring работает в режиме HS256 с общим секретом.
+type Keyring struct {
+	active *Key
+	keys   map[string]*Key
+	secret []byte
+}
+
+// NewHMAC создает Keyring, подписывающий токены алгоритмом HS256 общим секретом
+func NewHMAC(secret []byte) *Keyring {
+	return &Keyring{secret: secret}
+}
+
+// LoadDir загружает ключи из PEM-файлов каталога. Идентификатор ключа (kid) — имя файла без расширения .pem.
+// Поддерживаются закрытые ключи RSA (RS256) и Ed25519 (EdDSA) в формате PKCS#8 или PKCS#1
+// и открытые ключи в формате PKIX для проверки токенов, выданных уже удаленными закрытыми ключами.
+// Если activeKID не задан, активным становится последний по имени файла ключ с закрытой частью.
+func LoadDir(dir, activeKID string) (*Keyring, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("в каталоге %s нет ключей *.pem", dir)
+	}
+	sort.Strings(paths)
+
+	ring := &Keyring{keys: make(map[string]*Key, len(paths))}
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %s: %w", path, err)
+		}
+		ring.keys[key.ID] = key
+
+		if activeKID == "" && key.Private != nil {
+			ring.active = key
+		}
+	}
+
+	if activeKID != "" {
+		ring.active = ring.keys[activeKID]
+		if ring.active == nil {
+			return nil, fmt.Errorf("активный ключ %q не найден в %s", activeKID, dir)
+		}
+	}
+	if ring.active == nil || ring.active.Private == nil {
+		return nil, errors.New("нет закрытого ключа для подписи токенов")
+	}
+
+	return ring, nil
+}
+
+// loadKey разбирает PEM-файл с закрытым или открытым ключом
+func loadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("файл не содержит PEM-блок")
+	}
+
+	key := &Key{ID: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип PEM-блока %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.PublicKey = jwt.SigningMethodRS256, k, k.Public()
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.PublicKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodRS256, k
+	case ed25519.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип ключа %T, ожидается RSA или Ed25519", parsed)
+	}
+
+	return key, nil
+}
+
+// Asymmetric сообщает, что токены подписываются закрытыми ключами (RS256/EdDSA), а не общим секретом
+func (r *Keyring) Asymmetric() bool {
+	return r.keys != nil
+}
+
+// ActiveKID возвращает идентификатор ключа, которым подписываются новые токены.
+// В режиме HS256 возвращает пустую строку.
+func (r *Keyring) ActiveKID() string {
+	if r.active == nil {
+		return ""
+	}
+	return r.active.ID
+}
+
+// Sign подписывает claims активным ключом и добавляет его kid в заголовок токена
+func (r *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if r.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(r.secret)
+	}
+
+	token := jwt.NewWithClaims(r.active.Method, claims)
+	token.Header["kid"] = r.active.ID
+	return token.SignedString(r.active.Private)
+}
+
+// Keyfunc выбирает ключ проверки подписи по kid из заголовка токена
+func (r *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	if r.keys == nil {
+		if kid != "" {
+			return nil, fmt.Errorf("неизвестный ключ %q", kid)
+		}
+		return r.secret, nil
+	}
+
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный ключ %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("алгоритм %s не соответствует ключу %q", token.Method.Alg(), kid)
+	}
+	return key.PublicKey, nil
+}
+
+// Methods возвращает алгоритмы, которыми могут быть подписаны принимаемые токены
+func (r *Keyring) Methods() []string {
+	if r.keys == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+
+	seen := map[string]bool{}
+	var methods []string
+	for _, key := range r.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Parse проверяет подпись и срок действия токена
+func (r *Keyring) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, r.Keyfunc, jwt.WithValidMethods(r.Methods()))
+}
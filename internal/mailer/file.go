@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"userManagement/internal/utils"
+)
+
+// FileMailer не отправляет письма, а дописывает их в файл или в лог приложения.
+// Используется при разработке и в тестах.
+type FileMailer struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileMailer создает FileMailer. Если path пуст, письма пишутся в лог приложения.
+func NewFileMailer(path string) *FileMailer {
+	return &FileMailer{Path: path}
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	if m.Path == "" {
+		utils.Log.Infof("Письмо для %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.Path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(m.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "=== %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,13 @@
+package mailer
+
+// Message — письмо для отправки пользователю
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(msg Message) error
+}
@@ -1,37 +1,113 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"slices"
+	"strconv"
+	"userManagement/internal/config"
 	"userManagement/internal/dto"
+	"userManagement/internal/services"
 	"userManagement/internal/utils"
 )
 
-func Authorize(allowedRoles ...string) gin.HandlerFunc {
+// RequirePermission пропускает запрос, только если роль текущего пользователя обладает всеми указанными правами
+func RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Получаем информацию о текущем пользователе, которая была добавлена в контекст JWTAuthMiddleware
-		currentUser, exists := c.Get("currentUser")
-		if !exists {
-			utils.Log.Warn("Попытка доступа без авторизации")
-			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Пользователь не авторизован"})
-			c.Abort()
+		userInfo, ok := authorizedUser(c)
+		if !ok {
+			return
+		}
+
+		for _, permission := range permissions {
+			if !userInfo.HasPermission(permission) {
+				utils.Log.Warnf("Доступ запрещен для пользователя ID=%d: нет права %s", userInfo.ID, permission)
+				c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Недостаточно прав"})
+				c.Abort()
+				return
+			}
+		}
+
+		utils.Log.Infof("Доступ разрешен для пользователя ID=%d с ролью %s", userInfo.ID, userInfo.Role.Name)
+		c.Next()
+	}
+}
+
+// RequireGroupRole пропускает запрос к группе из параметра :id, если у пользователя есть право permission
+// на все группы или он состоит в этой группе с одной из ролей roles.
+// Роль в группе сохраняется в контексте как groupRole; пустое значение означает доступ по праву.
+// API-ключи ограничены своими правами, поэтому роль в группе по ключу не учитывается.
+func RequireGroupRole(permission string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userInfo, ok := authorizedUser(c)
+		if !ok {
 			return
 		}
 
-		for _, role := range allowedRoles {
-			if currentUser.(dto.UserInfo).Role.Name == role {
-				utils.Log.Infof("Доступ разрешен для пользователя ID=%d с ролью %s",
-					currentUser.(dto.UserInfo).ID,
-					currentUser.(dto.UserInfo).Role.Name)
+		if userInfo.HasPermission(permission) {
+			c.Set("groupRole", "")
+			c.Next()
+			return
+		}
+
+		groupID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err == nil && userInfo.APIKeyID == 0 {
+			role, err := services.GroupMemberRole(uint(groupID), userInfo.ID)
+			if err != nil && !errors.Is(err, services.ErrNotGroupMember) {
+				utils.Log.Errorf("Ошибка при проверке роли пользователя ID=%d в группе %d: %v", userInfo.ID, groupID, err)
+				c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Не удалось проверить права"})
+				c.Abort()
+				return
+			}
+			if slices.Contains(roles, role) {
+				utils.Log.Infof("Доступ разрешен для пользователя ID=%d с ролью %s в группе %d", userInfo.ID, role, groupID)
+				c.Set("groupRole", role)
 				c.Next()
 				return
 			}
 		}
 
-		utils.Log.Warnf("Доступ запрещен для пользователя ID=%d с ролью %s",
-			currentUser.(dto.UserInfo).ID,
-			currentUser.(dto.UserInfo).Role.Name)
+		utils.Log.Warnf("Доступ запрещен для пользователя ID=%d: нет права %s и нужной роли в группе %s", userInfo.ID, permission, c.Param("id"))
 		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Недостаточно прав"})
 		c.Abort()
 	}
 }
+
+// authorizedUser возвращает текущего пользователя, если ему можно выдавать права,
+// иначе отвечает на запрос ошибкой
+func authorizedUser(c *gin.Context) (dto.UserInfo, bool) {
+	// Получаем информацию о текущем пользователе, которая была добавлена в контекст JWTAuthMiddleware
+	currentUser, exists := c.Get("currentUser")
+	if !exists {
+		utils.Log.Warn("Попытка доступа без авторизации")
+		c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Пользователь не авторизован"})
+		c.Abort()
+		return dto.UserInfo{}, false
+	}
+	userInfo := currentUser.(dto.UserInfo)
+
+	// При политике limited пользователь без подтвержденного email не получает никаких прав
+	if config.EmailVerificationPolicy == config.EmailPolicyLimited && !userInfo.EmailVerified {
+		utils.Log.Warnf("Доступ запрещен для пользователя ID=%d: email не подтвержден", userInfo.ID)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Подтвердите email, чтобы получить доступ"})
+		c.Abort()
+		return dto.UserInfo{}, false
+	}
+	return userInfo, true
+}
+
+// RequireDefaultOrganization пропускает запрос, только если пользователь состоит в организации по умолчанию.
+// Ставится на общие для всех организаций настройки: сами организации, клиентов OpenID Connect и проверку журнала.
+func RequireDefaultOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("organizationID") != config.DefaultOrganizationID {
+			utils.Log.Warnf("Доступ запрещен для пользователя ID=%d: маршрут %s доступен только организации по умолчанию",
+				c.GetUint("userID"), c.FullPath())
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Недостаточно прав"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
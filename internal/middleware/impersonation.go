@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+	"userManagement/internal/services"
+	"userManagement/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DenyImpersonation закрывает маршрут для входа от имени пользователя. Ставится на действия, которые сотрудник
+// не должен совершать за пользователя: смену пароля и 2FA, управление ключами и сессиями, смену ролей и их прав,
+// изменение, блокировку и удаление других пользователей.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("realUserID") != c.GetUint("userID") {
+			utils.Log.Warnf("Сотрудник ID=%d обратился к маршруту %s от имени пользователя ID=%d",
+				c.GetUint("realUserID"), c.FullPath(), c.GetUint("userID"))
+			c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Действие недоступно при входе от имени пользователя"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// logImpersonatedRequest записывает в журнал каждый запрос, выполненный от имени пользователя
+func logImpersonatedRequest(c *gin.Context, impersonatorID, userID uint) {
+	err := services.LogAction(services.AuditEvent{
+		OrganizationID: c.GetUint("organizationID"),
+		ActorID:        impersonatorID,
+		Action:         models.ActionImpersonatedRequest,
+		TargetType:     models.TargetUser,
+		TargetID:       userID,
+		Changes: models.AuditChanges{}.
+			Add("method", nil, c.Request.Method).
+			Add("path", nil, c.Request.URL.Path).
+			Add("status", nil, c.Writer.Status()),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		utils.Log.Errorf("Не удалось записать запрос сотрудника ID=%d от имени пользователя ID=%d: %v", impersonatorID, userID, err)
+	}
+}
@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"userManagement/internal/config"
 	"userManagement/internal/dto"
 	"userManagement/internal/models"
+	"userManagement/internal/services"
 	"userManagement/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +17,13 @@ import (
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+
+		// Скрипты и CI авторизуются персональным API-ключом в отдельной схеме
+		if strings.HasPrefix(authHeader, apiKeyScheme) {
+			authenticateAPIKey(c, strings.TrimPrefix(authHeader, apiKeyScheme))
+			return
+		}
+
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			utils.Log.Warn("Отсутствует токен авторизации")
 			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Отсутствует токен авторизации"})
@@ -23,9 +32,8 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return config.JWTSecret, nil
-		})
+		// Ключ проверки выбирается по kid, поэтому токены, подписанные предыдущим ключом, действуют до истечения
+		token, err := config.Keys.Parse(tokenStr)
 
 		if err != nil || !token.Valid {
 			utils.Log.Warnf("Невалидный токен: %s", err)
@@ -42,8 +50,12 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		userIDFloat := claims["userID"].(float64)
-		if !ok {
+		// Служебные токены (подтверждение email, второй шаг входа) не дают доступа к API
+		_, hasPurpose := claims["purpose"]
+
+		userIDFloat, okID := claims["userID"].(float64)
+		jti, okJTI := claims["jti"].(string)
+		if !okID || !okJTI || hasPurpose {
 			utils.Log.Warn("Некорректный формат токена")
 			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Некорректный формат токена"})
 			c.Abort()
@@ -51,21 +63,175 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		}
 		userID := uint(userIDFloat)
 
+		impersonatorID, okAct := services.ImpersonatorID(claims)
+		if !okAct {
+			utils.Log.Warn("Некорректный claim act в токене")
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Некорректный формат токена"})
+			c.Abort()
+			return
+		}
+
+		if services.IsTokenRevoked(jti) {
+			utils.Log.Warnf("Попытка использования отозванного токена пользователем ID=%d", userID)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Токен отозван"})
+			c.Abort()
+			return
+		}
+
 		var user models.User
-		if err1 := config.DB.Preload("Role").First(&user, userID).Error; err1 != nil {
-			utils.Log.Warnf("Пользователь с ID=%d не найден:", userID)
-			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Пользователь не найден"})
+		if err1 := config.DB.Preload("Role.Permissions").First(&user, userID).Error; err1 != nil {
+			utils.Log.Warnf("Пользователь с ID=%d не найден:", userID)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Пользователь не найден"})
 			c.Abort()
 			return
 		}
 
-		c.Set("userID", user.ID)
-		c.Set("currentUser", dto.UserInfo{
-			ID:   user.ID,
-			Role: user.Role,
-		})
+		// Организация берется из токена и должна совпадать с организацией пользователя.
+		// Токены, выданные до разделения на организации, claim org не содержат.
+		if org, ok := claims["org"].(float64); ok && uint(org) != user.OrganizationID {
+			utils.Log.Warnf("Токен пользователя ID=%d выдан для другой организации", userID)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Невалидный токен"})
+			c.Abort()
+			return
+		}
+
+		// Проверяем, не завершал ли пользователь все сессии после выдачи токена
+		if services.IssuedBeforeRevocation(claims, user.SessionsRevokedAt) {
+			utils.Log.Warnf("Токен пользователя ID=%d выдан до завершения всех сессий", userID)
+			c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Токен отозван"})
+			c.Abort()
+			return
+		}
+
+		// Токены, выданные до появления сессий, не содержат sid и проверяются только по jti
+		if sid, ok := claims["sid"].(float64); ok && sid > 0 {
+			if err := services.TouchSession(uint(sid), userID, c.ClientIP()); err != nil {
+				if errors.Is(err, services.ErrSessionRevoked) {
+					utils.Log.Warnf("Запрос по токену завершенной сессии ID=%d пользователя ID=%d", uint(sid), userID)
+					c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Сессия завершена"})
+				} else {
+					utils.Log.Errorf("Ошибка при проверке сессии пользователя ID=%d: %v", userID, err)
+					c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка авторизации"})
+				}
+				c.Abort()
+				return
+			}
+			c.Set("sessionID", uint(sid))
+		}
+
+		// Вход от имени пользователя действует, пока сотрудник сохраняет право и доступ
+		var impersonator *models.User
+		if impersonatorID != 0 {
+			actor, err := services.CheckImpersonator(impersonatorID, claims)
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidImpersonation) {
+					utils.Log.Warnf("Недействительный вход сотрудника ID=%d от имени пользователя ID=%d", impersonatorID, userID)
+					c.JSON(http.StatusUnauthorized, dto.ResponseError{Message: "Вход от имени пользователя больше не действует"})
+				} else {
+					utils.Log.Errorf("Ошибка при проверке сотрудника ID=%d: %v", impersonatorID, err)
+					c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка авторизации"})
+				}
+				c.Abort()
+				return
+			}
+			impersonator = &actor
+		}
+
+		if !checkUserAccess(c, &user) {
+			return
+		}
+
+		expiresAt, _ := claims.GetExpirationTime()
+		if expiresAt != nil {
+			c.Set("tokenExpiresAt", expiresAt.Time)
+		}
+		c.Set("tokenID", jti)
+		setCurrentUser(c, &user, nil, impersonator)
+
+		if impersonator != nil {
+			utils.Log.Infof("Сотрудник ID=%d выполняет запрос от имени пользователя ID=%d", impersonator.ID, user.ID)
+			c.Next()
+			logImpersonatedRequest(c, impersonator.ID, user.ID)
+			return
+		}
 
 		utils.Log.Infof("Успешная авторизация пользователя ID=%d с ролью %s", user.ID, user.Role.Name)
 		c.Next()
 	}
 }
+
+// checkUserAccess проверяет, что пользователь не заблокирован, подтвердил email, если этого требует политика,
+// и настроил 2FA, если она обязательна для роли. При отказе отправляет ответ и прерывает запрос.
+func checkUserAccess(c *gin.Context, user *models.User) bool {
+	// Заблокированный пользователь теряет доступ сразу, не дожидаясь истечения токена
+	if err := services.CheckBan(config.DB, user); err != nil {
+		if errors.Is(err, services.ErrUserBanned) {
+			utils.Log.Warnf("Запрос заблокированного пользователя ID=%d", user.ID)
+			c.JSON(http.StatusForbidden, dto.BannedResponse{
+				Message:     "Учетная запись заблокирована",
+				Reason:      user.BanReason,
+				BannedUntil: user.BannedUntil,
+			})
+		} else {
+			utils.Log.Errorf("Ошибка при проверке блокировки пользователя ID=%d: %v", user.ID, err)
+			c.JSON(http.StatusInternalServerError, dto.ResponseError{Message: "Ошибка авторизации"})
+		}
+		c.Abort()
+		return false
+	}
+
+	// Токены, выданные до включения политики block_login, тоже перестают действовать
+	if err := services.CheckEmailVerified(user); err != nil {
+		utils.Log.Warnf("Запрос пользователя ID=%d с неподтвержденным email", user.ID)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Email не подтвержден"})
+		c.Abort()
+		return false
+	}
+
+	// Пока обязательная для роли 2FA не настроена, доступны только эндпоинты ее настройки
+	if services.TwoFactorRequired(user) && !twoFactorSetupAllowed(c.FullPath()) {
+		utils.Log.Warnf("Пользователь ID=%d не настроил обязательную 2FA", user.ID)
+		c.JSON(http.StatusForbidden, dto.ResponseError{Message: "Для вашей роли необходимо настроить двухфакторную аутентификацию"})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
+
+// setCurrentUser сохраняет в контексте данные пользователя, по которым проверяются права.
+// userID — пользователь, от имени которого выполняется запрос, realUserID — тот, кто его на самом деле выполняет:
+// при входе от имени пользователя это сотрудник. organizationID — организация, которой ограничены все запросы к данным.
+func setCurrentUser(c *gin.Context, user *models.User, apiKey *models.APIKey, impersonator *models.User) {
+	info := dto.UserInfo{
+		ID:             user.ID,
+		OrganizationID: user.OrganizationID,
+		Role:           user.Role,
+		EmailVerified:  user.EmailVerified,
+	}
+	if apiKey != nil {
+		info.APIKeyID = apiKey.ID
+		info.APIKeyScopes = apiKey.Scopes
+		c.Set("apiKeyID", apiKey.ID)
+	}
+
+	realUserID := user.ID
+	if impersonator != nil {
+		info.ImpersonatorID = impersonator.ID
+		realUserID = impersonator.ID
+	}
+
+	c.Set("userID", user.ID)
+	c.Set("realUserID", realUserID)
+	c.Set("organizationID", user.OrganizationID)
+	c.Set("currentUser", info)
+}
+
+// twoFactorSetupAllowed сообщает, доступен ли маршрут пользователю, который еще не настроил обязательную 2FA
+func twoFactorSetupAllowed(path string) bool {
+	switch path {
+	case "/users/me", "/auth/logout", "/auth/logout-all":
+		return true
+	}
+	return strings.HasPrefix(path, "/users/me/2fa/")
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"userManagement/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedirectToOAuthLogin отправляет браузер, пришедший на /oauth/authorize без токена, на страницу входа OIDC_LOGIN_URL
+// с теми же параметрами запроса. Страница входа авторизует пользователя и повторяет запрос с его access-токеном.
+// Запросы с заголовком Authorization и запросы без настроенной страницы входа проходят дальше без изменений.
+func RedirectToOAuthLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.OIDCLoginURL == "" || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+
+		// Адрес проверяется при запуске
+		target, _ := url.Parse(config.OIDCLoginURL)
+		query := target.Query()
+		for key, values := range c.Request.URL.Query() {
+			query[key] = values
+		}
+		target.RawQuery = query.Encode()
+
+		c.Redirect(http.StatusFound, target.String())
+		c.Abort()
+	}
+}
@@ -1,12 +1,121 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"time"
 )
 
+// ActionCode — код действия в журнале активности
+type ActionCode string
+
+const (
+	ActionUserCreated         ActionCode = "user.created"
+	ActionUserUpdated         ActionCode = "user.updated"
+	ActionUserDeleted         ActionCode = "user.deleted"
+	ActionUserRoleChanged     ActionCode = "user.role_changed"
+	ActionUserBanned          ActionCode = "user.banned"
+	ActionUserUnbanned        ActionCode = "user.unbanned"
+	ActionUserUnlocked        ActionCode = "user.unlocked"
+	ActionUserSessionsRevoked ActionCode = "user.sessions_revoked"
+	ActionUserSessionRevoked  ActionCode = "user.session_revoked"
+	ActionUserPasswordChanged ActionCode = "user.password_changed"
+	ActionUser2FAEnabled      ActionCode = "user.2fa_enabled"
+	ActionUser2FADisabled     ActionCode = "user.2fa_disabled"
+	ActionUserRecoveryCodes   ActionCode = "user.2fa_recovery_codes_regenerated"
+	ActionUserSSOLinked       ActionCode = "user.sso_linked"
+	ActionUserAPIKeyCreated   ActionCode = "user.api_key_created"
+	ActionUserAPIKeyRevoked   ActionCode = "user.api_key_revoked"
+	ActionUserImpersonated    ActionCode = "user.impersonated"
+	ActionImpersonatedRequest ActionCode = "user.impersonated_request"
+	ActionAuthLogoutAll       ActionCode = "auth.logout_all"
+	ActionAuthPasswordReset   ActionCode = "auth.password_reset"
+	ActionAuthEmailVerified   ActionCode = "auth.email_verified"
+	ActionAuthRecoveryCode    ActionCode = "auth.2fa_recovery_code_used"
+	ActionAuthAccountLocked   ActionCode = "auth.account_locked"
+	ActionRoleCreated         ActionCode = "role.created"
+	ActionRoleUpdated         ActionCode = "role.updated"
+	ActionRoleDeleted         ActionCode = "role.deleted"
+	ActionRolePermissions     ActionCode = "role.permissions_changed"
+	ActionGroupCreated        ActionCode = "group.created"
+	ActionGroupUpdated        ActionCode = "group.updated"
+	ActionGroupDeleted        ActionCode = "group.deleted"
+	ActionGroupMemberAdded    ActionCode = "group.member_added"
+	ActionGroupMemberRemoved  ActionCode = "group.member_removed"
+	ActionGroupMemberRole     ActionCode = "group.member_role_changed"
+	ActionOAuthClientCreated  ActionCode = "oauth_client.created"
+	ActionOAuthClientDeleted  ActionCode = "oauth_client.deleted"
+	ActionOAuthConsent        ActionCode = "oauth_client.consent_granted"
+	ActionOrganizationCreated ActionCode = "organization.created"
+	ActionOrganizationUpdated ActionCode = "organization.updated"
+)
+
+// Типы объектов, над которыми совершается действие
+const (
+	TargetUser         = "user"
+	TargetRole         = "role"
+	TargetGroup        = "group"
+	TargetOAuthClient  = "oauth_client"
+	TargetOrganization = "organization"
+)
+
+// FieldChange — значение поля до и после изменения
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditChanges — изменения полей объекта, хранятся в БД как JSON
+type AuditChanges map[string]FieldChange
+
+// Add добавляет изменение поля, если значение действительно изменилось
+func (a AuditChanges) Add(field string, from, to interface{}) AuditChanges {
+	if !reflect.DeepEqual(from, to) {
+		a[field] = FieldChange{From: from, To: to}
+	}
+	return a
+}
+
+func (a AuditChanges) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (a *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("неподдерживаемый тип для AuditChanges: %T", value)
+	}
+}
+
+// ActivityLog — запись журнала активности. OrganizationID не заполнен у записей,
+// сделанных до разделения на организации: они относятся к организации по умолчанию.
 type ActivityLog struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id"`
-	Action    string    `json:"action"`
-	Timestamp time.Time `json:"timestamp"`
+	ID             uint         `json:"id" gorm:"primaryKey"`
+	OrganizationID *uint        `json:"organization_id,omitempty" gorm:"index"`
+	UserID         uint         `json:"user_id" gorm:"index"`
+	Action         ActionCode   `json:"action" gorm:"index"`
+	TargetType     string       `json:"target_type,omitempty" gorm:"index:idx_activity_logs_target"`
+	TargetID       *uint        `json:"target_id,omitempty" gorm:"index:idx_activity_logs_target"`
+	Changes        AuditChanges `json:"changes,omitempty" gorm:"type:json"`
+	IP             string       `json:"ip,omitempty"`
+	UserAgent      string       `json:"user_agent,omitempty"`
+	Timestamp      time.Time    `json:"timestamp"`
+	PrevHash       string       `json:"prev_hash"`
+	Hash           string       `json:"hash" gorm:"index"`
 }
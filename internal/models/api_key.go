@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// APIKey — персональный ключ для скриптов и CI. Ключ действует от имени владельца,
+// но только в пределах своих областей (Scopes) — кодов прав, которые есть у роли владельца.
+// По Prefix ключ находится в БД, секретная часть хранится только в виде хэша.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"uniqueIndex;not null"`
+	SecretHash string     `json:"-" gorm:"not null"`
+	Scopes     StringList `json:"scopes" gorm:"type:json"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsExpired сообщает, истек ли срок действия ключа на момент now. Ключ без ExpiresAt бессрочный.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
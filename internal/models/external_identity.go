@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ExternalIdentity связывает пользователя с учетной записью у внешнего провайдера OpenID Connect.
+// Пользователь определяется по паре провайдер + sub, а не по email, который у провайдера может измениться.
+type ExternalIdentity struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"index;not null"`
+	Provider    string     `json:"provider" gorm:"not null;uniqueIndex:idx_external_identity"`
+	Subject     string     `json:"subject" gorm:"not null;uniqueIndex:idx_external_identity"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -1,15 +1,64 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"gorm.io/gorm"
 	"time"
 )
 
+// StringMap — произвольные пары ключ-значение, хранятся в БД как JSON
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan читает пустой объект как nil, чтобы пустые и незаполненные значения не различались
+func (m *StringMap) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип для StringMap: %T", value)
+	}
+
+	var parsed map[string]string
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	if len(parsed) == 0 {
+		parsed = nil
+	}
+	*m = parsed
+	return nil
+}
+
+// Group - группа пользователей. Группы образуют дерево через ParentID:
+// участники подгруппы считаются участниками всех ее родительских групп
 type Group struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"unique;not null"`
-	Users     []User         `json:"users" gorm:"many2many:group_users"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint           `json:"organization_id" gorm:"uniqueIndex:idx_groups_org_name"`
+	Name           string         `json:"name" gorm:"uniqueIndex:idx_groups_org_name;not null"`
+	Description    string         `json:"description" gorm:"type:text"`
+	Metadata       StringMap      `json:"metadata" gorm:"type:json"`
+	ParentID       *uint          `json:"parent_id" gorm:"index"`
+	Users          []User         `json:"users,omitempty" gorm:"many2many:group_users"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Роли участника внутри группы. Они не дают прав на остальной сервис,
+// а только определяют, кто может управлять составом группы
+const (
+	GroupRoleOwner   = "owner"
+	GroupRoleManager = "manager"
+	GroupRoleMember  = "member"
+)
+
+// GroupMember описывает членство пользователя в группе и хранится в связующей таблице group_users
+type GroupMember struct {
+	GroupID   uint      `json:"group_id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	Role      string    `json:"role" gorm:"type:varchar(16);not null;default:member"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (GroupMember) TableName() string {
+	return "group_users"
+}
+
+// BeforeCreate проставляет роль участника, если связь создается через ассоциацию без явной роли
+func (m *GroupMember) BeforeCreate(tx *gorm.DB) error {
+	if m.Role == "" {
+		m.Role = GroupRoleMember
+	}
+	return nil
+}
+
+// IsGroupRole сообщает, является ли строка известной ролью участника группы
+func IsGroupRole(role string) bool {
+	switch role {
+	case GroupRoleOwner, GroupRoleManager, GroupRoleMember:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// LoginIPFailure — неудачные попытки входа с одного IP в текущем окне. Хранится в БД,
+// чтобы ограничение действовало на всех экземплярах сервиса и не сбрасывалось при перезапуске.
+type LoginIPFailure struct {
+	IP          string    `gorm:"primaryKey"`
+	Failures    int       `gorm:"not null"`
+	WindowStart time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Области доступа OpenID Connect, которые поддерживает сервис
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// StringList — список строк, хранится в БД как JSON
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("неподдерживаемый тип для StringList: %T", value)
+	}
+}
+
+// Contains сообщает, входит ли значение в список
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthClient — приложение, которое использует сервис как провайдер OpenID Connect.
+// Публичные клиенты (SPA, мобильные приложения) не имеют секрета и полагаются только на PKCE.
+type OAuthClient struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ClientID     string     `json:"client_id" gorm:"uniqueIndex;not null"`
+	SecretHash   string     `json:"-"`
+	Name         string     `json:"name" gorm:"not null"`
+	RedirectURIs StringList `json:"redirect_uris" gorm:"type:json"`
+	Public       bool       `json:"public" gorm:"default:false"`
+	// FirstParty — собственные приложения компании, для них согласие пользователя не запрашивается
+	FirstParty bool      `json:"first_party" gorm:"default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AuthorizationCode — одноразовый код авторизации, хранится только его хэш
+type AuthorizationCode struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	CodeHash            string     `json:"-" gorm:"uniqueIndex;not null"`
+	ClientID            string     `json:"client_id" gorm:"index;not null"`
+	UserID              uint       `json:"user_id" gorm:"index;not null"`
+	RedirectURI         string     `json:"redirect_uri"`
+	Scope               string     `json:"scope"`
+	Nonce               string     `json:"nonce"`
+	CodeChallenge       string     `json:"-"`
+	CodeChallengeMethod string     `json:"-"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	UsedAt              *time.Time `json:"used_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// OAuthConsent — согласие пользователя на доступ стороннего клиента к указанным областям
+type OAuthConsent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_oauth_consent;not null"`
+	ClientID  string    `json:"client_id" gorm:"uniqueIndex:idx_oauth_consent;not null"`
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
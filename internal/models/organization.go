@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DefaultOrganizationSlug — организация, создаваемая при первом запуске. В нее переносятся данные,
+// появившиеся до разделения на организации, и только ее администраторы управляют другими организациями.
+const DefaultOrganizationSlug = "default"
+
+// Organization — клиент сервиса. Пользователи, группы, роли и журнал активности принадлежат организации,
+// email пользователя и названия групп и ролей уникальны в ее пределах.
+type Organization struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	Slug string `json:"slug" gorm:"uniqueIndex;not null"`
+	// RegistrationOpen — можно ли зарегистрироваться в организации самостоятельно через /auth/register.
+	// Открыта только у организации по умолчанию, остальным пользователей создает администратор
+	RegistrationOpen bool      `json:"registration_open" gorm:"not null;default:false"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
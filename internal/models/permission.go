@@ -0,0 +1,27 @@
+package models
+
+// Коды прав доступа, проверяемые в маршрутах
+const (
+	PermUsersRead        = "users:read"
+	PermUsersCreate      = "users:create"
+	PermUsersUpdate      = "users:update"
+	PermUsersDelete      = "users:delete"
+	PermUsersBan         = "users:ban"
+	PermUsersRoles       = "users:roles"
+	PermUsersSessions    = "users:sessions"
+	PermUsersImpersonate = "users:impersonate"
+	PermActivityRead     = "activity:read"
+	PermUsersActivity    = "users:activity"
+	PermGroupsRead       = "groups:read"
+	PermGroupsWrite      = "groups:write"
+	PermRolesRead        = "roles:read"
+	PermRolesWrite       = "roles:write"
+	PermOAuthClients     = "oauth:clients"
+	PermOrganizations    = "organizations:manage"
+)
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"unique;not null" json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RecoveryCode — одноразовый код восстановления для входа без приложения-аутентификатора.
+// Хранится только SHA-256 хэш кода.
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"uniqueIndex;not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
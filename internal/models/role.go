@@ -1,8 +1,38 @@
 package models
 
+// Встроенные роли, создаваемые при первом запуске
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleUser      = "user"
+)
+
 type Role struct {
-	ID          uint   `gorm:"primaryKey" json:"id"`
-	Name        string `gorm:"unique;not null" json:"name"`
-	Description string `json:"description"`
-	Users       []User `gorm:"foreignKey:RoleID"`
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	OrganizationID uint         `gorm:"uniqueIndex:idx_roles_org_name" json:"organization_id"`
+	Name           string       `gorm:"uniqueIndex:idx_roles_org_name;not null" json:"name"`
+	Description    string       `json:"description"`
+	Require2FA     bool         `gorm:"column:require_2fa;default:false" json:"require_2fa"`
+	Permissions    []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	Users          []User       `gorm:"foreignKey:RoleID"`
+}
+
+// HasPermission проверяет, входит ли право в набор прав роли.
+// Права должны быть предварительно загружены через Preload("Role.Permissions").
+func (r *Role) HasPermission(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, permission := range r.Permissions {
+		if permission.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProtected сообщает, что роль нельзя удалить или переименовать:
+// admin должен существовать всегда, а user назначается при регистрации
+func (r *Role) IsProtected() bool {
+	return r.Name == RoleAdmin || r.Name == RoleUser
 }
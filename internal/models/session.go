@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Session — вход пользователя на устройстве. Сессия соответствует семейству refresh-токенов (FamilyID)
+// и живет, пока его можно обновлять. Access-токены сессии содержат ее ID в claim sid.
+type Session struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	FamilyID   string     `json:"-" gorm:"uniqueIndex;not null"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	TokenID    string     `json:"token_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive сообщает, действует ли сессия на момент now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
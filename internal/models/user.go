@@ -6,15 +6,39 @@ import (
 )
 
 type User struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Name         string         `json:"name" gorm:"required"`
-	Email        string         `json:"email" gorm:"uniqueIndex;not null"`
-	PasswordHash string         `json:"-"`
-	RoleID       uint           `json:"role_id"`
-	Role         *Role          `json:"role" gorm:"constraint:OnUpdate:CASCADE;"`
-	IsBanned     bool           `json:"is_banned" gorm:"default:false"`
-	Groups       []Group        `json:"groups" gorm:"many2many:group_users"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID    uint           `json:"organization_id" gorm:"uniqueIndex:idx_users_org_email"`
+	Name              string         `json:"name" gorm:"required"`
+	Email             string         `json:"email" gorm:"uniqueIndex:idx_users_org_email;not null"`
+	PasswordHash      string         `json:"-"`
+	RoleID            uint           `json:"role_id"`
+	Role              *Role          `json:"role" gorm:"constraint:OnUpdate:CASCADE;"`
+	EmailVerified     bool           `json:"email_verified" gorm:"default:false"`
+	EmailVerifiedAt   *time.Time     `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled  bool           `json:"two_factor_enabled" gorm:"default:false"`
+	TwoFactorSecret   string         `json:"-"`
+	TwoFactorLastStep int64          `json:"-"`
+	TwoFactorFailures int            `json:"-" gorm:"default:0"`
+	IsBanned          bool           `json:"is_banned" gorm:"default:false"`
+	BanReason         string         `json:"ban_reason,omitempty"`
+	BannedUntil       *time.Time     `json:"banned_until,omitempty"`
+	FailedLogins      int            `json:"failed_logins" gorm:"default:0"`
+	LockedUntil       *time.Time     `json:"locked_until,omitempty"`
+	LockoutCount      int            `json:"-" gorm:"default:0"`
+	Groups            []Group        `json:"groups" gorm:"many2many:group_users"`
+	SessionsRevokedAt *time.Time     `json:"-"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// IsLocked сообщает, действует ли временная блокировка после неудачных попыток входа на момент now
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// IsBanActive сообщает, действует ли блокировка пользователя на момент now.
+// Блокировка без BannedUntil бессрочная.
+func (u *User) IsBanActive(now time.Time) bool {
+	return u.IsBanned && (u.BannedUntil == nil || now.Before(*u.BannedUntil))
 }
@@ -3,10 +3,22 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"userManagement/internal/handlers"
+	"userManagement/internal/middleware"
 )
 
 func RegisterAuthRoutes(r *gin.Engine) {
 	auth := r.Group("/auth")
 	auth.POST("/register", handlers.Register)
 	auth.POST("/login", handlers.Login)
+	auth.POST("/refresh", handlers.Refresh)
+	auth.POST("/2fa/verify", handlers.VerifyTwoFactor)
+	auth.POST("/forgot-password", handlers.ForgotPassword)
+	auth.POST("/reset-password", handlers.ResetPassword)
+	auth.GET("/verify-email", handlers.VerifyEmail)
+	auth.POST("/resend-verification", handlers.ResendVerification)
+	auth.GET("/sso", handlers.GetSSOProviders)
+	auth.GET("/sso/:provider/login", handlers.SSOLogin)
+	auth.GET("/sso/:provider/callback", handlers.SSOCallback)
+	auth.POST("/logout", middleware.JWTAuthMiddleware(), middleware.DenyAPIKey(), handlers.Logout)
+	auth.POST("/logout-all", middleware.JWTAuthMiddleware(), middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.LogoutAll)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"userManagement/internal/handlers"
 	"userManagement/internal/middleware"
+	"userManagement/internal/models"
 )
 
 func RegisterGroupRoutes(r *gin.Engine) {
@@ -11,13 +12,19 @@ func RegisterGroupRoutes(r *gin.Engine) {
 	groups.Use(middleware.JWTAuthMiddleware())
 	{
 		// Создание, обновление и удаление групп
-		groups.POST("/", middleware.Authorize("admin", "moderator"), handlers.CreateGroups)
-		groups.GET("/", middleware.Authorize("admin", "moderator"), handlers.GetGroups)
-		groups.PUT("/:id", middleware.Authorize("admin", "moderator"), handlers.UpdateGroup)
-		groups.DELETE("/:id", middleware.Authorize("admin", "moderator"), handlers.DeleteGroup)
+		groups.POST("/", middleware.RequirePermission(models.PermGroupsWrite), handlers.CreateGroups)
+		groups.GET("/", middleware.RequirePermission(models.PermGroupsRead), handlers.GetGroups)
+		// Просматривать свою группу и ее состав могут все ее участники
+		groups.GET("/:id", middleware.RequireGroupRole(models.PermGroupsRead, models.GroupRoleOwner, models.GroupRoleManager, models.GroupRoleMember), handlers.GetGroup)
+		groups.GET("/:id/users", middleware.RequireGroupRole(models.PermGroupsRead, models.GroupRoleOwner, models.GroupRoleManager, models.GroupRoleMember), handlers.GetGroupUsers)
+		groups.PUT("/:id", middleware.RequirePermission(models.PermGroupsWrite), handlers.UpdateGroup)
+		groups.DELETE("/:id", middleware.RequirePermission(models.PermGroupsWrite), handlers.DeleteGroup)
+		groups.GET("/:id/effective-members", middleware.RequirePermission(models.PermGroupsRead), handlers.GetEffectiveGroupMembers)
 
-		// Управление участниками группы
-		groups.POST("/:id/users", middleware.Authorize("admin", "moderator"), handlers.AddUserToGroup)
-		groups.DELETE("/:id/users/:user_id", middleware.Authorize("admin", "moderator"), handlers.RemoveUserFromGroup)
+		// Управление участниками группы: кроме права groups:write доступно владельцам и менеджерам самой группы
+		groups.PATCH("/:id/users", middleware.RequireGroupRole(models.PermGroupsWrite, models.GroupRoleOwner, models.GroupRoleManager), handlers.UpdateGroupMembers)
+		groups.POST("/:id/users", middleware.RequireGroupRole(models.PermGroupsWrite, models.GroupRoleOwner, models.GroupRoleManager), handlers.AddUserToGroup)
+		groups.DELETE("/:id/users/:user_id", middleware.RequireGroupRole(models.PermGroupsWrite, models.GroupRoleOwner, models.GroupRoleManager), handlers.RemoveUserFromGroup)
+		groups.PATCH("/:id/users/:user_id", middleware.RequireGroupRole(models.PermGroupsWrite, models.GroupRoleOwner), handlers.UpdateGroupMemberRole)
 	}
 }
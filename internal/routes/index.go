@@ -1,9 +1,19 @@
 package routes
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"userManagement/internal/config"
+)
 
 func RegisterAllRoutes(r *gin.Engine) {
 	RegisterUserRoutes(r)
 	RegisterGroupRoutes(r)
+	RegisterRoleRoutes(r)
 	RegisterAuthRoutes(r)
+	RegisterWellKnownRoutes(r)
+	// Провайдер OpenID Connect доступен только с асимметричными ключами подписи
+	if config.OIDCEnabled {
+		RegisterOAuthRoutes(r)
+	}
+	RegisterOrganizationRoutes(r)
 }
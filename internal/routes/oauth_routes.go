@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"userManagement/internal/handlers"
+	"userManagement/internal/middleware"
+	"userManagement/internal/models"
+)
+
+func RegisterOAuthRoutes(r *gin.Engine) {
+	oauth := r.Group("/oauth")
+	{
+		// Запрос авторизации выполняется от имени вошедшего пользователя, браузер без токена уходит на страницу входа
+		// Сотрудник не может выдавать сторонним приложениям доступ к данным пользователя
+		oauth.GET("/authorize", middleware.RedirectToOAuthLogin(), middleware.JWTAuthMiddleware(), middleware.DenyImpersonation(), handlers.Authorize)
+		oauth.POST("/authorize", middleware.JWTAuthMiddleware(), middleware.DenyImpersonation(), handlers.AuthorizeConsent)
+
+		// Клиенты аутентифицируются своими секретами и токенами, а не токенами сервиса
+		oauth.POST("/token", handlers.Token)
+		oauth.GET("/userinfo", handlers.UserInfo)
+		oauth.POST("/userinfo", handlers.UserInfo)
+	}
+
+	clients := r.Group("/oauth/clients")
+	// Клиенты общие для всех организаций, поэтому ими управляет только организация по умолчанию
+	clients.Use(middleware.JWTAuthMiddleware(), middleware.RequireDefaultOrganization(), middleware.RequirePermission(models.PermOAuthClients))
+	{
+		clients.GET("/", handlers.GetOAuthClients)
+		clients.POST("/", handlers.CreateOAuthClient)
+		clients.DELETE("/:id", handlers.DeleteOAuthClient)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"userManagement/internal/handlers"
+	"userManagement/internal/middleware"
+	"userManagement/internal/models"
+)
+
+func RegisterOrganizationRoutes(r *gin.Engine) {
+	organizations := r.Group("/organizations")
+	organizations.Use(
+		middleware.JWTAuthMiddleware(),
+		middleware.DenyImpersonation(),
+		middleware.RequireDefaultOrganization(),
+		middleware.RequirePermission(models.PermOrganizations),
+	)
+	{
+		organizations.GET("/", handlers.GetOrganizations)
+		organizations.POST("/", handlers.CreateOrganization)
+		organizations.PATCH("/:id", handlers.UpdateOrganization)
+	}
+}
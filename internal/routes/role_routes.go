@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"userManagement/internal/handlers"
+	"userManagement/internal/middleware"
+	"userManagement/internal/models"
+)
+
+func RegisterRoleRoutes(r *gin.Engine) {
+	roles := r.Group("/roles")
+	roles.Use(middleware.JWTAuthMiddleware())
+	{
+		roles.GET("/", middleware.RequirePermission(models.PermRolesRead), handlers.GetRoles)
+		roles.GET("/:id", middleware.RequirePermission(models.PermRolesRead), handlers.GetRole)
+		// Права ролей меняются только от собственного имени: иначе сотрудник расширил бы права через чужую учетную запись
+		roles.POST("/", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermRolesWrite), handlers.CreateRole)
+		roles.PUT("/:id", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermRolesWrite), handlers.UpdateRole)
+		roles.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermRolesWrite), handlers.DeleteRole)
+		roles.PUT("/:id/permissions", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermRolesWrite), handlers.UpdateRolePermissions)
+	}
+
+	permissions := r.Group("/permissions")
+	permissions.Use(middleware.JWTAuthMiddleware())
+	{
+		permissions.GET("/", middleware.RequirePermission(models.PermRolesRead), handlers.GetPermissions)
+	}
+}
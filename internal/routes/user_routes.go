@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"userManagement/internal/handlers"
 	"userManagement/internal/middleware"
+	"userManagement/internal/models"
 )
 
 func RegisterUserRoutes(r *gin.Engine) {
@@ -11,15 +12,38 @@ func RegisterUserRoutes(r *gin.Engine) {
 	users.Use(middleware.JWTAuthMiddleware())
 	{
 		users.GET("/me", handlers.GetProfile)
+		// Учетными данными можно управлять только после собственного входа: не по API-ключу и не от имени пользователя
+		users.PATCH("/me/password", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.ChangePassword)
+		users.POST("/me/2fa/setup", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.SetupTwoFactor)
+		users.POST("/me/2fa/confirm", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.ConfirmTwoFactor)
+		users.POST("/me/2fa/disable", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.DisableTwoFactor)
+		users.POST("/me/2fa/recovery-codes", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.RegenerateRecoveryCodes)
+		users.GET("/me/api-keys", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.GetAPIKeys)
+		users.POST("/me/api-keys", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.CreateAPIKey)
+		users.DELETE("/me/api-keys/:keyId", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.DeleteAPIKey)
+		users.GET("/me/sessions", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.GetMySessions)
+		users.DELETE("/me/sessions/:sessionId", middleware.DenyAPIKey(), middleware.DenyImpersonation(), handlers.RevokeMySession)
 
-		users.GET("/", middleware.Authorize("admin", "moderator"), handlers.GetUsers)
-		users.POST("/", middleware.Authorize("admin"), handlers.CreateUser)
-		users.PUT("/:id", middleware.Authorize("admin", "moderator"), handlers.UpdateUser)
-		users.DELETE("/:id", middleware.Authorize("admin"), handlers.DeleteUser)
-		users.PATCH("/:id/role", middleware.Authorize("admin"), handlers.UpdateUserRole)
+		users.GET("/", middleware.RequirePermission(models.PermUsersRead), handlers.GetUsers)
+		users.GET("/:id", middleware.RequirePermission(models.PermUsersRead), handlers.GetUser)
+		users.POST("/", middleware.RequirePermission(models.PermUsersCreate), handlers.CreateUser)
+		users.PUT("/:id", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersUpdate), handlers.UpdateUser)
+		users.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersDelete), handlers.DeleteUser)
+		users.PATCH("/:id/role", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersRoles), handlers.UpdateUserRole)
+		users.POST("/:id/impersonate", middleware.DenyAPIKey(), middleware.DenyImpersonation(),
+			middleware.RequirePermission(models.PermUsersImpersonate), handlers.ImpersonateUser)
 
-		users.GET("/activity", middleware.Authorize("admin"), handlers.GetActivityLogs)
-		users.PATCH("/:id/ban", middleware.Authorize("admin"), handlers.BanUser)
-		users.PATCH("/:id/unban", middleware.Authorize("admin"), handlers.UnbanUser)
+		users.GET("/activity", middleware.RequirePermission(models.PermActivityRead), handlers.GetActivityLogs)
+		// Цепочка журнала общая для всех организаций
+		users.GET("/activity/verify", middleware.RequireDefaultOrganization(), middleware.RequirePermission(models.PermActivityRead), handlers.VerifyActivityChain)
+		users.GET("/:id/groups", middleware.RequirePermission(models.PermGroupsRead), handlers.GetUserGroups)
+		users.GET("/:id/effective-groups", middleware.RequirePermission(models.PermGroupsRead), handlers.GetEffectiveUserGroups)
+		users.GET("/:id/activity", middleware.RequirePermission(models.PermUsersActivity), handlers.GetUserActivity)
+		users.PATCH("/:id/ban", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersBan), handlers.BanUser)
+		users.PATCH("/:id/unban", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersBan), handlers.UnbanUser)
+		users.PATCH("/:id/unlock", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersBan), handlers.UnlockUser)
+		users.GET("/:id/sessions", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersSessions), handlers.GetUserSessions)
+		users.DELETE("/:id/sessions", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersSessions), handlers.RevokeUserSessions)
+		users.DELETE("/:id/sessions/:sessionId", middleware.DenyImpersonation(), middleware.RequirePermission(models.PermUsersSessions), handlers.RevokeUserSession)
 	}
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"userManagement/internal/config"
+	"userManagement/internal/handlers"
+)
+
+func RegisterWellKnownRoutes(r *gin.Engine) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", handlers.GetJWKS)
+		if config.OIDCEnabled {
+			wellKnown.GET("/openid-configuration", handlers.GetOpenIDConfiguration)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package seed
 
 import (
 	"fmt"
+	"time"
 	"userManagement/internal/utils"
 
 	"golang.org/x/crypto/bcrypt"
@@ -9,9 +10,10 @@ import (
 	"userManagement/internal/models"
 )
 
-func SeedAdmin(db *gorm.DB) error {
+// SeedAdmin создает встроенного админа в организации по умолчанию
+func SeedAdmin(db *gorm.DB, organizationID uint) error {
 	var count int64
-	if err := db.Model(&models.User{}).Where("email = ?", "admin@example.com").Count(&count).Error; err != nil {
+	if err := db.Model(&models.User{}).Where("organization_id = ? AND email = ?", organizationID, "admin@example.com").Count(&count).Error; err != nil {
 		utils.Log.Errorf("Ошибка при проверке существующего админа: %v", err)
 		return fmt.Errorf("Не удалось проверить наличие админа: %w", err)
 	}
@@ -30,16 +32,21 @@ func SeedAdmin(db *gorm.DB) error {
 
 	// Ищем роль админа
 	var role models.Role
-	if errRole := db.Where("name = ?", "admin").First(&role).Error; errRole != nil {
+	if errRole := db.Where("organization_id = ? AND name = ?", organizationID, models.RoleAdmin).First(&role).Error; errRole != nil {
 		utils.Log.Errorf("Ошибка при получении роли админа: %v", errRole)
 		return fmt.Errorf("Не удалось получить роль админа: %w", errRole)
 	}
 
+	// Адрес встроенного админа считается подтвержденным, иначе при политике block_login в систему не войти
+	now := time.Now()
 	admin := models.User{
-		Name:         "admin",
-		Email:        "admin@example.com",
-		PasswordHash: string(hashedPassword),
-		RoleID:       role.ID,
+		OrganizationID:  organizationID,
+		Name:            "admin",
+		Email:           "admin@example.com",
+		PasswordHash:    string(hashedPassword),
+		RoleID:          role.ID,
+		EmailVerified:   true,
+		EmailVerifiedAt: &now,
 	}
 
 	if err := db.Create(&admin).Error; err != nil {
@@ -0,0 +1,27 @@
+package seed
+
+import (
+	"fmt"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// SeedDefaultOrganization создает организацию по умолчанию, если ее еще нет
+func SeedDefaultOrganization(db *gorm.DB) (models.Organization, error) {
+	organization := models.Organization{Name: "Default", Slug: models.DefaultOrganizationSlug, RegistrationOpen: true}
+	if err := db.Where("slug = ?", organization.Slug).FirstOrCreate(&organization).Error; err != nil {
+		utils.Log.Errorf("Не удалось создать организацию по умолчанию: %v", err)
+		return organization, fmt.Errorf("Не удалось создать организацию по умолчанию: %w", err)
+	}
+	return organization, nil
+}
+
+// SeedOrganization создает встроенные роли новой организации и выдает им права по умолчанию
+func SeedOrganization(db *gorm.DB, organizationID uint) error {
+	if err := SeedRoles(db, organizationID); err != nil {
+		return err
+	}
+	return GrantDefaultPermissions(db, organizationID)
+}
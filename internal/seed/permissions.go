@@ -0,0 +1,125 @@
+package seed
+
+import (
+	"fmt"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// defaultPermissions — встроенные права и роли, которым они выдаются при первом создании.
+// Роль admin получает все права всегда.
+var defaultPermissions = []struct {
+	Name        string
+	Description string
+	Roles       []string
+}{
+	{models.PermUsersRead, "Просмотр пользователей", []string{models.RoleModerator}},
+	{models.PermUsersCreate, "Создание пользователей", nil},
+	{models.PermUsersUpdate, "Редактирование пользователей", []string{models.RoleModerator}},
+	{models.PermUsersDelete, "Удаление пользователей", nil},
+	{models.PermUsersBan, "Блокировка пользователей", nil},
+	{models.PermUsersRoles, "Назначение ролей пользователям", nil},
+	{models.PermUsersSessions, "Управление сессиями пользователей", nil},
+	{models.PermUsersImpersonate, "Вход от имени пользователя", nil},
+	{models.PermActivityRead, "Просмотр журнала активности", nil},
+	{models.PermUsersActivity, "Просмотр активности отдельного пользователя", []string{models.RoleModerator}},
+	{models.PermGroupsRead, "Просмотр групп", []string{models.RoleModerator}},
+	{models.PermGroupsWrite, "Управление группами", []string{models.RoleModerator}},
+	{models.PermRolesRead, "Просмотр ролей и прав", nil},
+	{models.PermRolesWrite, "Управление ролями и правами", nil},
+	{models.PermOAuthClients, "Управление клиентами OpenID Connect", nil},
+	{models.PermOrganizations, "Управление организациями", nil},
+}
+
+// SeedPermissions создает недостающие права. Новое право выдается встроенным ролям всех организаций.
+func SeedPermissions(db *gorm.DB) error {
+	for _, item := range defaultPermissions {
+		var permission models.Permission
+		err := db.Where("name = ?", item.Name).First(&permission).Error
+		if err == nil {
+			// Право уже существует: набор ролей мог быть изменен администратором, не трогаем его
+			continue
+		}
+		if err != gorm.ErrRecordNotFound {
+			utils.Log.Errorf("Ошибка при получении права %s: %v", item.Name, err)
+			return fmt.Errorf("Не удалось получить право %s: %w", item.Name, err)
+		}
+
+		permission = models.Permission{Name: item.Name, Description: item.Description}
+		if err := db.Create(&permission).Error; err != nil {
+			utils.Log.Errorf("Не удалось создать право %s: %v", item.Name, err)
+			return fmt.Errorf("Не удалось создать право %s: %w", item.Name, err)
+		}
+		utils.Log.Printf("Создано право %s", item.Name)
+
+		if len(item.Roles) == 0 {
+			continue
+		}
+		var roles []models.Role
+		if err := db.Where("name IN ?", item.Roles).Find(&roles).Error; err != nil {
+			return fmt.Errorf("Не удалось получить роли для права %s: %w", item.Name, err)
+		}
+		for _, role := range roles {
+			if err := db.Model(&role).Association("Permissions").Append(&permission); err != nil {
+				return fmt.Errorf("Не удалось выдать право %s роли %s: %w", item.Name, role.Name, err)
+			}
+		}
+	}
+
+	// Роль admin в каждой организации всегда обладает всеми правами
+	var admins []models.Role
+	if err := db.Where("name = ?", models.RoleAdmin).Find(&admins).Error; err != nil {
+		utils.Log.Errorf("Ошибка при получении ролей админа: %v", err)
+		return fmt.Errorf("Не удалось получить роли админа: %w", err)
+	}
+	for _, admin := range admins {
+		if err := grantAllPermissions(db, admin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GrantDefaultPermissions выдает встроенным ролям новой организации права по умолчанию
+func GrantDefaultPermissions(db *gorm.DB, organizationID uint) error {
+	for _, item := range defaultPermissions {
+		if len(item.Roles) == 0 {
+			continue
+		}
+		var permission models.Permission
+		if err := db.Where("name = ?", item.Name).First(&permission).Error; err != nil {
+			return fmt.Errorf("Не удалось получить право %s: %w", item.Name, err)
+		}
+		var roles []models.Role
+		if err := db.Where("organization_id = ? AND name IN ?", organizationID, item.Roles).Find(&roles).Error; err != nil {
+			return fmt.Errorf("Не удалось получить роли для права %s: %w", item.Name, err)
+		}
+		for _, role := range roles {
+			if err := db.Model(&role).Association("Permissions").Append(&permission); err != nil {
+				return fmt.Errorf("Не удалось выдать право %s роли %s: %w", item.Name, role.Name, err)
+			}
+		}
+	}
+
+	var admin models.Role
+	if err := db.Where("organization_id = ? AND name = ?", organizationID, models.RoleAdmin).First(&admin).Error; err != nil {
+		return fmt.Errorf("Не удалось получить роль админа: %w", err)
+	}
+	return grantAllPermissions(db, admin)
+}
+
+func grantAllPermissions(db *gorm.DB, admin models.Role) error {
+	var permissions []models.Permission
+	if err := db.Find(&permissions).Error; err != nil {
+		return fmt.Errorf("Не удалось получить список прав: %w", err)
+	}
+	if err := db.Model(&admin).Association("Permissions").Append(&permissions); err != nil {
+		utils.Log.Errorf("Не удалось выдать права роли админа: %v", err)
+		return fmt.Errorf("Не удалось выдать права роли админа: %w", err)
+	}
+
+	return nil
+}
@@ -8,16 +8,18 @@ import (
 	"gorm.io/gorm"
 )
 
-func SeedRoles(db *gorm.DB) error {
+// SeedRoles создает встроенные роли организации
+func SeedRoles(db *gorm.DB, organizationID uint) error {
 	roles := []models.Role{
-		{Name: "admin", Description: "Администратор"},
-		{Name: "moderator", Description: "Модератор"},
-		{Name: "user", Description: "Пользователь"},
+		// Для ролей с расширенными правами по умолчанию требуется двухфакторная аутентификация
+		{OrganizationID: organizationID, Name: models.RoleAdmin, Description: "Администратор", Require2FA: true},
+		{OrganizationID: organizationID, Name: models.RoleModerator, Description: "Модератор", Require2FA: true},
+		{OrganizationID: organizationID, Name: models.RoleUser, Description: "Пользователь"},
 	}
 
 	for _, role := range roles {
 		var existing models.Role
-		if err := db.Where("Name = ?", role.Name).First(&existing).Error; err != nil {
+		if err := db.Where("organization_id = ? AND name = ?", organizationID, role.Name).First(&existing).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				if err1 := db.Create(&role).Error; err1 != nil {
 					utils.Log.Errorf("Не удалось создать роль %s: %v", role.Name, err1)
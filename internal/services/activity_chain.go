@@ -0,0 +1,151 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/dto"
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// activityChainLockID — ключ advisory-блокировки, сериализующей запись в цепочку
+const activityChainLockID = 7_310_001
+
+// activityChainBatchSize — размер пачки при проверке цепочки
+const activityChainBatchSize = 500
+
+// appendToActivityChain связывает запись с последней записью журнала и сохраняет ее.
+// Advisory-блокировка не дает двум параллельным записям сослаться на один и тот же хэш.
+func appendToActivityChain(log *models.ActivityLog) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", activityChainLockID).Error; err != nil {
+			return err
+		}
+
+		var last models.ActivityLog
+		if err := tx.Order("id desc").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+
+		// Postgres хранит время с точностью до микросекунд: округляем заранее, чтобы хэш совпадал при проверке
+		log.Timestamp = log.Timestamp.UTC().Truncate(time.Microsecond)
+		log.PrevHash = last.Hash
+
+		hash, err := computeActivityHash(*log)
+		if err != nil {
+			return err
+		}
+		log.Hash = hash
+
+		return tx.Create(log).Error
+	})
+}
+
+// computeActivityHash вычисляет SHA-256 от содержимого записи и хэша предыдущей записи
+func computeActivityHash(log models.ActivityLog) (string, error) {
+	// Пустые изменения хранятся в БД как NULL, поэтому не различаем nil и пустой набор
+	var changes []byte
+	if len(log.Changes) > 0 {
+		var err error
+		if changes, err = json.Marshal(log.Changes); err != nil {
+			return "", err
+		}
+	}
+
+	// Организация не входит в хэш записей, сделанных до разделения на организации, чтобы их хэши не изменились
+	payload, err := json.Marshal(struct {
+		OrganizationID *uint             `json:"organization_id,omitempty"`
+		UserID         uint              `json:"user_id"`
+		Action         models.ActionCode `json:"action"`
+		TargetType     string            `json:"target_type"`
+		TargetID       *uint             `json:"target_id"`
+		Changes        string            `json:"changes"`
+		IP             string            `json:"ip"`
+		UserAgent      string            `json:"user_agent"`
+		Timestamp      string            `json:"timestamp"`
+		PrevHash       string            `json:"prev_hash"`
+	}{
+		OrganizationID: log.OrganizationID,
+		UserID:         log.UserID,
+		Action:         log.Action,
+		TargetType:     log.TargetType,
+		TargetID:       log.TargetID,
+		Changes:        string(changes),
+		IP:             log.IP,
+		UserAgent:      log.UserAgent,
+		Timestamp:      log.Timestamp.UTC().Format(time.RFC3339Nano),
+		PrevHash:       log.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyActivityChain проходит журнал активности по порядку и сообщает о первом нарушенном звене.
+// Записи, созданные до появления цепочки, не имеют хэша и пропускаются, если идут в начале журнала.
+// Удаление последних записей цепочкой не обнаруживается: для этого HeadHash нужно сохранять вне БД.
+func VerifyActivityChain() (dto.ActivityChainReport, error) {
+	report := dto.ActivityChainReport{Valid: true}
+
+	var (
+		logs     []models.ActivityLog
+		prevHash string
+		started  bool
+	)
+
+	result := config.DB.Order("id").FindInBatches(&logs, activityChainBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, log := range logs {
+			if log.Hash == "" {
+				if started {
+					return failChain(&report, log.ID, "запись без хэша внутри цепочки")
+				}
+				report.Legacy++
+				continue
+			}
+			started = true
+
+			if log.PrevHash != prevHash {
+				return failChain(&report, log.ID, "ссылка на предыдущую запись не совпадает: запись удалена или вставлена")
+			}
+
+			hash, err := computeActivityHash(log)
+			if err != nil {
+				return err
+			}
+			if hash != log.Hash {
+				return failChain(&report, log.ID, "хэш не совпадает с содержимым: запись изменена")
+			}
+
+			prevHash = log.Hash
+			report.Checked++
+		}
+		return nil
+	})
+
+	if result.Error != nil && report.Valid {
+		return report, result.Error
+	}
+
+	if report.Valid {
+		report.HeadHash = prevHash
+	}
+	return report, nil
+}
+
+// errChainBroken прерывает обход журнала после обнаружения нарушения
+var errChainBroken = errors.New("цепочка журнала активности нарушена")
+
+func failChain(report *dto.ActivityChainReport, id uint, reason string) error {
+	report.Valid = false
+	report.BrokenAtID = &id
+	report.Reason = reason
+	return errChainBroken
+}
@@ -2,23 +2,44 @@ package services
 
 import (
 	"time"
-	"userManagement/internal/config"
 	"userManagement/internal/models"
 	"userManagement/internal/utils"
 )
 
-func LogAction(userID uint, action string) error {
+// AuditEvent — структурированное событие журнала активности
+type AuditEvent struct {
+	OrganizationID uint
+	ActorID        uint
+	Action         models.ActionCode
+	TargetType     string
+	TargetID       uint
+	Changes        models.AuditChanges
+	IP             string
+	UserAgent      string
+}
+
+func LogAction(event AuditEvent) error {
 	log := models.ActivityLog{
-		UserID:    userID,
-		Action:    action,
-		Timestamp: time.Now(),
+		UserID:     event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		Changes:    event.Changes,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		Timestamp:  time.Now(),
+	}
+	if event.OrganizationID != 0 {
+		log.OrganizationID = &event.OrganizationID
+	}
+	if event.TargetID != 0 {
+		log.TargetID = &event.TargetID
 	}
 
-	if err := config.DB.Create(&log).Error; err != nil {
-		utils.Log.Errorf("Ошибка при записи действия пользователя (ID: %d, Action: %s): %v", userID, action, err)
+	if err := appendToActivityChain(&log); err != nil {
+		utils.Log.Errorf("Ошибка при записи действия пользователя (ID: %d, Action: %s): %v", event.ActorID, event.Action, err)
 		return err
 	}
 
-	utils.Log.Infof("Записано действие пользователя (ID: %d, Action: %s)", userID, action)
+	utils.Log.Infof("Записано действие пользователя (ID: %d, Action: %s, Target: %s/%d)", event.ActorID, event.Action, event.TargetType, event.TargetID)
 	return nil
 }
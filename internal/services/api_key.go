@@ -0,0 +1,143 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyMarker упрощает поиск утекших ключей в логах и репозиториях
+	apiKeyMarker = "umk_"
+	// apiKeyPrefixBytes — длина открытой части ключа, по которой он находится в БД.
+	// Префикс уникален, поэтому он достаточно длинный, чтобы выпуск ключа не упирался в совпадения
+	apiKeyPrefixBytes = 8
+	maxAPIKeysPerUser = 25
+	// apiKeyTouchInterval ограничивает запись времени последнего использования, чтобы не обновлять строку на каждый запрос
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey   = errors.New("недействительный API-ключ")
+	ErrAPIKeyScope     = errors.New("область API-ключа не входит в права роли")
+	ErrAPIKeyLimit     = errors.New("достигнуто максимальное число API-ключей")
+	ErrAPIKeyExpiresAt = errors.New("срок действия API-ключа должен быть в будущем")
+)
+
+// CreateAPIKey выдает пользователю API-ключ. Области ключа ограничены правами его роли.
+// Ключ целиком возвращается один раз, в БД хранится только хэш секретной части.
+func CreateAPIKey(userID uint, name string, scopes []string, expiresAt *time.Time) (models.APIKey, string, error) {
+	var user models.User
+	if err := config.DB.Preload("Role.Permissions").First(&user, userID).Error; err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	normalized := make(models.StringList, 0, len(scopes))
+	for _, scope := range scopes {
+		if !user.Role.HasPermission(scope) {
+			return models.APIKey{}, "", fmt.Errorf("%w: %s", ErrAPIKeyScope, scope)
+		}
+		if !normalized.Contains(scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return models.APIKey{}, "", ErrAPIKeyExpiresAt
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.APIKey{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return models.APIKey{}, "", err
+	}
+	if count >= maxAPIKeysPerUser {
+		return models.APIKey{}, "", ErrAPIKeyLimit
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return models.APIKey{}, "", err
+	}
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	key := models.APIKey{
+		UserID:     userID,
+		Name:       name,
+		Prefix:     hex.EncodeToString(prefixBytes),
+		SecretHash: utils.HashToken(secret),
+		Scopes:     normalized,
+		ExpiresAt:  expiresAt,
+	}
+	if err := config.DB.Create(&key).Error; err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, apiKeyMarker + key.Prefix + "_" + secret, nil
+}
+
+// ListAPIKeys возвращает ключи пользователя без секретов
+func ListAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := config.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// DeleteAPIKey отзывает ключ пользователя. Чужой ключ считается ненайденным.
+func DeleteAPIKey(userID, keyID uint) (models.APIKey, error) {
+	var key models.APIKey
+	if err := config.DB.Where("id = ? AND user_id = ?", keyID, userID).First(&key).Error; err != nil {
+		return key, err
+	}
+	return key, config.DB.Delete(&key).Error
+}
+
+// AuthenticateAPIKey проверяет ключ из заголовка Authorization и отмечает время его использования
+func AuthenticateAPIKey(raw string) (models.APIKey, error) {
+	var key models.APIKey
+
+	rest, ok := strings.CutPrefix(raw, apiKeyMarker)
+	if !ok {
+		return key, ErrInvalidAPIKey
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != hex.EncodedLen(apiKeyPrefixBytes) || secret == "" {
+		return key, ErrInvalidAPIKey
+	}
+
+	if err := config.DB.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return key, ErrInvalidAPIKey
+		}
+		return key, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(secret)), []byte(key.SecretHash)) != 1 {
+		return key, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		return key, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := config.DB.Model(&key).Update("last_used_at", now).Error; err != nil {
+			utils.Log.Errorf("Не удалось обновить время использования API-ключа ID=%d: %v", key.ID, err)
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
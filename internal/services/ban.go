@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrUserBanned = errors.New("пользователь заблокирован")
+
+// CheckBan проверяет блокировку пользователя и снимает временную блокировку, срок которой истек.
+// Возвращает ErrUserBanned, если блокировка действует.
+func CheckBan(db *gorm.DB, user *models.User) error {
+	if !user.IsBanned {
+		return nil
+	}
+
+	if user.IsBanActive(time.Now()) {
+		return ErrUserBanned
+	}
+
+	if err := db.Model(user).Updates(map[string]interface{}{
+		"is_banned":    false,
+		"ban_reason":   "",
+		"banned_until": nil,
+	}).Error; err != nil {
+		return err
+	}
+
+	user.IsBanned = false
+	user.BanReason = ""
+	user.BannedUntil = nil
+
+	utils.Log.Infof("Срок блокировки пользователя %s истек, блокировка снята", user.Email)
+	return nil
+}
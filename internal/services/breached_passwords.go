@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+	"userManagement/internal/config"
+	"userManagement/internal/utils"
+)
+
+// breachedPrefixLength — длина префикса SHA-1, по которому хэши разбиты на группы,
+// как в k-anonymity API Have I Been Pwned
+const breachedPrefixLength = 5
+
+var (
+	breachedOnce   sync.Once
+	breachedHashes map[string]map[string]struct{}
+)
+
+// loadBreachedHashes загружает файл утекших паролей. Каждая строка содержит SHA-1 хэш пароля
+// в шестнадцатеричном виде, после двоеточия может идти число утечек (формат HIBP).
+// Пустые строки и строки, начинающиеся с #, пропускаются.
+func loadBreachedHashes(path string) map[string]map[string]struct{} {
+	file, err := os.Open(path)
+	if err != nil {
+		utils.Log.Errorf("Не удалось открыть файл утекших паролей %s, проверка отключена: %v", path, err)
+		return nil
+	}
+	defer file.Close()
+
+	hashes := make(map[string]map[string]struct{})
+	count := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			continue
+		}
+
+		prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+		if hashes[prefix] == nil {
+			hashes[prefix] = make(map[string]struct{})
+		}
+		hashes[prefix][suffix] = struct{}{}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		utils.Log.Errorf("Ошибка чтения файла утекших паролей %s, проверка отключена: %v", path, err)
+		return nil
+	}
+
+	utils.Log.Infof("Загружено %d хэшей утекших паролей из %s", count, path)
+	return hashes
+}
+
+// IsPasswordBreached сообщает, встречается ли пароль в списке утекших.
+// Если файл не задан или не загрузился, всегда возвращает false.
+func IsPasswordBreached(password string) bool {
+	if config.Password.BreachedFile == "" {
+		return false
+	}
+
+	breachedOnce.Do(func() {
+		breachedHashes = loadBreachedHashes(config.Password.BreachedFile)
+	})
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := breachedHashes[hash[:breachedPrefixLength]][hash[breachedPrefixLength:]]
+	return found
+}
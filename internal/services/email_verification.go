@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/mailer"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// emailVerificationPurpose отличает токен подтверждения email от access-токена
+const emailVerificationPurpose = "email_verification"
+
+var (
+	ErrInvalidVerificationToken = errors.New("недействительная или просроченная ссылка для подтверждения email")
+	ErrEmailNotVerified         = errors.New("email не подтвержден")
+	ErrEmailAlreadyVerified     = errors.New("email уже подтвержден")
+)
+
+// CheckEmailVerified возвращает ErrEmailNotVerified, если политика запрещает вход без подтвержденного email
+func CheckEmailVerified(user *models.User) error {
+	if config.EmailVerificationPolicy == config.EmailPolicyBlockLogin && !user.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// SendVerificationEmail отправляет пользователю подписанную ссылку для подтверждения email.
+// Ссылка привязана к текущему адресу и перестает действовать после его смены.
+func SendVerificationEmail(user models.User) error {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose": emailVerificationPurpose,
+		"userID":  user.ID,
+		"email":   user.Email,
+		"iat":     now.Unix(),
+		"exp":     now.Add(config.EmailVerificationTTL).Unix(),
+	})
+
+	signed, err := token.SignedString(config.JWTSecret)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/verify-email?token=%s", config.AppBaseURL, url.QueryEscape(signed))
+	return config.Mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nДля подтверждения адреса электронной почты перейдите по ссылке:\n%s\n\nСсылка действительна %s.\nЕсли вы не регистрировались, просто проигнорируйте это письмо.",
+			user.Name, link, config.EmailVerificationTTL,
+		),
+	})
+}
+
+// ResendVerification повторно отправляет ссылку для подтверждения email.
+// Если пользователь не найден или уже подтвердил адрес, ничего не делает, чтобы не раскрывать наличие учетной записи.
+func ResendVerification(organizationID uint, email string) error {
+	var user models.User
+	if err := config.DB.Where("organization_id = ? AND email = ?", organizationID, email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Log.Warnf("Запрошено подтверждение для несуществующего email: %s", email)
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerified {
+		utils.Log.Infof("Email %s уже подтвержден, повторная отправка не требуется", email)
+		return nil
+	}
+
+	return SendVerificationEmail(user)
+}
+
+// VerifyEmail подтверждает email пользователя по токену из письма.
+// Если адрес уже подтвержден, возвращает пользователя вместе с ErrEmailAlreadyVerified.
+func VerifyEmail(rawToken string) (models.User, error) {
+	var user models.User
+
+	token, err := jwt.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		return config.JWTSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return user, ErrInvalidVerificationToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != emailVerificationPurpose {
+		return user, ErrInvalidVerificationToken
+	}
+
+	userID, okID := claims["userID"].(float64)
+	email, okEmail := claims["email"].(string)
+	if !okID || !okEmail {
+		return user, ErrInvalidVerificationToken
+	}
+
+	if err := config.DB.First(&user, uint(userID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, ErrInvalidVerificationToken
+		}
+		return user, err
+	}
+
+	// Ссылка выдана для другого адреса: после смены email нужно подтвердить новый
+	if user.Email != email {
+		return user, ErrInvalidVerificationToken
+	}
+
+	if user.EmailVerified {
+		return user, ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	if err := config.DB.Model(&user).Updates(map[string]interface{}{
+		"email_verified":    true,
+		"email_verified_at": now,
+	}).Error; err != nil {
+		return user, err
+	}
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+
+	return user, nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotGroupMember     = errors.New("пользователь не состоит в группе")
+	ErrAlreadyGroupMember = errors.New("пользователь уже состоит в группе")
+	ErrLastGroupOwner     = errors.New("нельзя лишить группу последнего владельца")
+)
+
+// GroupMemberRole возвращает роль пользователя в группе или ErrNotGroupMember
+func GroupMemberRole(groupID, userID uint) (string, error) {
+	var member models.GroupMember
+	err := config.DB.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrNotGroupMember
+	}
+	return member.Role, err
+}
+
+// GroupMemberRoles возвращает роли участников указанных групп: group_id -> user_id -> роль
+func GroupMemberRoles(groupIDs []uint) (map[uint]map[uint]string, error) {
+	roles := make(map[uint]map[uint]string, len(groupIDs))
+	if len(groupIDs) == 0 {
+		return roles, nil
+	}
+
+	var members []models.GroupMember
+	if err := config.DB.Where("group_id IN ?", groupIDs).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if roles[member.GroupID] == nil {
+			roles[member.GroupID] = make(map[uint]string)
+		}
+		roles[member.GroupID][member.UserID] = member.Role
+	}
+	return roles, nil
+}
+
+// GroupMemberRolesOf возвращает роли указанных пользователей в группе: user_id -> роль.
+// Пользователей, которые не состоят в группе, в результате нет.
+func GroupMemberRolesOf(groupID uint, userIDs []uint) (map[uint]string, error) {
+	roles := make(map[uint]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return roles, nil
+	}
+
+	var members []models.GroupMember
+	if err := config.DB.Where("group_id = ? AND user_id IN ?", groupID, userIDs).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		roles[member.UserID] = member.Role
+	}
+	return roles, nil
+}
+
+// UpdateGroupMembers добавляет и исключает участников группы в одной транзакции.
+// Уже состоящие в группе при добавлении и не состоящие при исключении пропускаются,
+// поэтому запрос можно безопасно повторить. Возвращает ID фактически добавленных и исключенных.
+// Если keepOwner установлен, группа не может лишиться последнего владельца.
+func UpdateGroupMembers(groupID uint, add []models.GroupMember, remove []uint, keepOwner bool) (added, removed []uint, err error) {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		added, removed = nil, nil
+		removedOwner := false
+
+		for _, userID := range remove {
+			var member models.GroupMember
+			if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+			if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error; err != nil {
+				return err
+			}
+			removedOwner = removedOwner || member.Role == models.GroupRoleOwner
+			removed = append(removed, userID)
+		}
+
+		for _, member := range add {
+			member.GroupID = groupID
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				added = append(added, member.UserID)
+			}
+		}
+
+		if keepOwner && removedOwner {
+			var owners int64
+			if err := tx.Model(&models.GroupMember{}).Where("group_id = ? AND role = ?", groupID, models.GroupRoleOwner).
+				Count(&owners).Error; err != nil {
+				return err
+			}
+			if owners == 0 {
+				return ErrLastGroupOwner
+			}
+		}
+		return nil
+	})
+	return added, removed, err
+}
+
+// AddGroupMember добавляет пользователя в группу с указанной ролью
+func AddGroupMember(groupID, userID uint, role string) error {
+	if _, err := GroupMemberRole(groupID, userID); err == nil {
+		return ErrAlreadyGroupMember
+	} else if !errors.Is(err, ErrNotGroupMember) {
+		return err
+	}
+
+	return config.DB.Create(&models.GroupMember{GroupID: groupID, UserID: userID, Role: role}).Error
+}
+
+// SetGroupMemberRole меняет роль участника группы и возвращает прежнюю.
+// Последнего владельца нельзя понизить, если keepOwner установлен.
+func SetGroupMemberRole(groupID, userID uint, role string, keepOwner bool) (string, error) {
+	var previous string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var member models.GroupMember
+		if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotGroupMember
+			}
+			return err
+		}
+		previous = member.Role
+
+		if keepOwner && member.Role == models.GroupRoleOwner && role != models.GroupRoleOwner {
+			if err := ensureAnotherOwner(tx, groupID, userID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Update("role", role).Error
+	})
+	return previous, err
+}
+
+// RemoveGroupMember исключает пользователя из группы и возвращает его роль в ней.
+// Последнего владельца нельзя исключить, если keepOwner установлен.
+func RemoveGroupMember(groupID, userID uint, keepOwner bool) (string, error) {
+	var role string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		role, err = removeGroupMember(tx, groupID, userID, keepOwner)
+		return err
+	})
+	return role, err
+}
+
+// removeGroupMember исключает участника в переданной транзакции, см. RemoveGroupMember
+func removeGroupMember(tx *gorm.DB, groupID, userID uint, keepOwner bool) (string, error) {
+	var member models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrNotGroupMember
+		}
+		return "", err
+	}
+
+	if keepOwner && member.Role == models.GroupRoleOwner {
+		if err := ensureAnotherOwner(tx, groupID, userID); err != nil {
+			return member.Role, err
+		}
+	}
+
+	return member.Role, tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error
+}
+
+// ensureAnotherOwner проверяет, что у группы останется владелец помимо указанного пользователя
+func ensureAnotherOwner(tx *gorm.DB, groupID, userID uint) error {
+	var owners int64
+	if err := tx.Model(&models.GroupMember{}).
+		Where("group_id = ? AND user_id <> ? AND role = ?", groupID, userID, models.GroupRoleOwner).
+		Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners == 0 {
+		return ErrLastGroupOwner
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrParentGroupNotFound = errors.New("родительская группа не найдена")
+	ErrGroupCycle          = errors.New("группа не может быть вложена в саму себя или в свою подгруппу")
+)
+
+// Подгруппы и группы-предки выбираются рекурсивными CTE по groups.parent_id.
+// UNION вместо UNION ALL отбрасывает повторы, поэтому запрос завершится даже на испорченных данных с циклом.
+const (
+	groupAncestorsCTE = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM groups WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT g.id, g.parent_id FROM groups g JOIN ancestors a ON g.id = a.parent_id WHERE g.deleted_at IS NULL
+) SELECT id FROM ancestors`
+
+	groupDescendantsCTE = `WITH RECURSIVE subgroups AS (
+	SELECT id FROM groups WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT g.id FROM groups g JOIN subgroups s ON g.parent_id = s.id WHERE g.deleted_at IS NULL
+) SELECT id FROM subgroups`
+
+	userGroupsCTE = `WITH RECURSIVE user_groups AS (
+	SELECT g.id, g.parent_id FROM groups g JOIN group_users gu ON gu.group_id = g.id
+	WHERE gu.user_id = ? AND g.deleted_at IS NULL
+	UNION
+	SELECT g.id, g.parent_id FROM groups g JOIN user_groups ug ON g.id = ug.parent_id WHERE g.deleted_at IS NULL
+) SELECT id FROM user_groups`
+)
+
+// groupTreeLockID — старшая часть ключа advisory-блокировки дерева групп, младшая — ID организации
+const groupTreeLockID = 7_310_002
+
+// CreateGroup создает группу, проверяя родителя под блокировкой дерева групп организации
+func CreateGroup(group *models.Group) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockGroupTree(tx, group.OrganizationID); err != nil {
+			return err
+		}
+		if err := validateGroupParent(tx, group.OrganizationID, 0, group.ParentID); err != nil {
+			return err
+		}
+		return tx.Create(group).Error
+	})
+}
+
+// SaveGroup сохраняет изменения группы. Проверка родителя и запись выполняются под одной блокировкой,
+// поэтому два параллельных переноса не могут вместе образовать цикл.
+func SaveGroup(group *models.Group) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockGroupTree(tx, group.OrganizationID); err != nil {
+			return err
+		}
+		if err := validateGroupParent(tx, group.OrganizationID, group.ID, group.ParentID); err != nil {
+			return err
+		}
+		return tx.Save(group).Error
+	})
+}
+
+// DeleteGroup удаляет группу вместе с записями о членстве, а ее подгруппы переносит к ее родителю.
+// Все шаги выполняются в одной транзакции, чтобы не оставить ссылок на удаленную группу.
+func DeleteGroup(group models.Group) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockGroupTree(tx, group.OrganizationID); err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		// Подгруппы не теряют место в иерархии, а поднимаются на уровень выше
+		if err := tx.Model(&models.Group{}).Where("parent_id = ?", group.ID).Update("parent_id", group.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&group).Error
+	})
+}
+
+// lockGroupTree сериализует изменения иерархии групп организации до конца транзакции
+func lockGroupTree(tx *gorm.DB, organizationID uint) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", int64(groupTreeLockID)<<32|int64(organizationID)).Error
+}
+
+// validateGroupParent проверяет, что группу groupID можно вложить в parentID:
+// родитель существует в той же организации и не является самой группой или ее подгруппой.
+// Для новой группы groupID равен 0, пустой parentID делает группу корневой.
+func validateGroupParent(tx *gorm.DB, organizationID, groupID uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var parent models.Group
+	if err := tx.Scopes(TenantScope(organizationID)).First(&parent, *parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrParentGroupNotFound
+		}
+		return err
+	}
+	if groupID == 0 {
+		return nil
+	}
+
+	// Цикл возникает, если сама группа окажется среди предков нового родителя
+	var ancestorIDs []uint
+	if err := tx.Raw(groupAncestorsCTE, parent.ID).Scan(&ancestorIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range ancestorIDs {
+		if id == groupID {
+			return ErrGroupCycle
+		}
+	}
+	return nil
+}
+
+// EffectiveGroupMembers возвращает участников группы вместе с участниками всех ее подгрупп
+func EffectiveGroupMembers(groupID uint) ([]models.User, error) {
+	var users []models.User
+	err := config.DB.Preload("Role").
+		Where("id IN (?)", config.DB.Table("group_users").Select("user_id").
+			Where("group_id IN (?)", config.DB.Raw(groupDescendantsCTE, groupID))).
+		Order("id").
+		Find(&users).Error
+	return users, err
+}
+
+// EffectiveUserGroups возвращает группы, в которых пользователь состоит напрямую, и все их родительские группы
+func EffectiveUserGroups(userID uint) ([]models.Group, error) {
+	var groups []models.Group
+	err := config.DB.Where("id IN (?)", config.DB.Raw(userGroupsCTE, userID)).Order("id").Find(&groups).Error
+	return groups, err
+}
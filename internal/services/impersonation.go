@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrImpersonateSelf       = errors.New("нельзя войти от имени самого себя")
+	ErrImpersonatePrivileged = errors.New("нельзя войти от имени пользователя с правами, которых нет у сотрудника")
+	ErrInvalidImpersonation  = errors.New("вход от имени пользователя больше не действует")
+)
+
+// IssueImpersonationToken выдает короткоживущий access-токен пользователя target для сотрудника actor.
+// Сотрудник указывается в claim act (RFC 8693), токен не привязан к сессии и не обновляется.
+// Роли actor и target должны быть загружены вместе с правами.
+func IssueImpersonationToken(actor, target models.User) (token string, jti string, err error) {
+	if actor.ID == target.ID {
+		return "", "", ErrImpersonateSelf
+	}
+	// Иначе через чужую учетную запись можно было бы получить права, которых у сотрудника нет
+	if target.Role.HasPermission(models.PermUsersImpersonate) {
+		return "", "", ErrImpersonatePrivileged
+	}
+	if target.Role != nil {
+		for _, permission := range target.Role.Permissions {
+			if !actor.Role.HasPermission(permission.Name) {
+				return "", "", ErrImpersonatePrivileged
+			}
+		}
+	}
+	if err := CheckBan(config.DB, &target); err != nil {
+		return "", "", err
+	}
+
+	jti, err = utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	token, err = config.Keys.Sign(jwt.MapClaims{
+		"jti":    jti,
+		"org":    target.OrganizationID,
+		"userID": target.ID,
+		"role":   target.Role,
+		"act":    map[string]interface{}{"sub": actor.ID},
+		"iat":    now.Unix(),
+		"iat_ms": now.UnixMilli(),
+		"exp":    now.Add(config.ImpersonationTTL).Unix(),
+	})
+	return token, jti, err
+}
+
+// ImpersonatorID возвращает ID сотрудника из claim act или 0, если токен выдан самому пользователю
+func ImpersonatorID(claims jwt.MapClaims) (uint, bool) {
+	act, ok := claims["act"]
+	if !ok {
+		return 0, true
+	}
+	actMap, ok := act.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	sub, ok := actMap["sub"].(float64)
+	if !ok || sub <= 0 {
+		return 0, false
+	}
+	return uint(sub), true
+}
+
+// CheckImpersonator проверяет, что сотрудник, выдавший себе токен, по-прежнему может входить от имени пользователей:
+// не заблокирован, не завершал все сессии после выдачи токена и не лишился права
+func CheckImpersonator(actorID uint, claims jwt.MapClaims) (models.User, error) {
+	var actor models.User
+	if err := config.DB.Preload("Role.Permissions").First(&actor, actorID).Error; err != nil {
+		return actor, ErrInvalidImpersonation
+	}
+
+	if IssuedBeforeRevocation(claims, actor.SessionsRevokedAt) {
+		return actor, ErrInvalidImpersonation
+	}
+	if !actor.Role.HasPermission(models.PermUsersImpersonate) {
+		return actor, ErrInvalidImpersonation
+	}
+	if err := CheckBan(config.DB, &actor); err != nil {
+		if errors.Is(err, ErrUserBanned) {
+			return actor, ErrInvalidImpersonation
+		}
+		return actor, err
+	}
+	return actor, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAccountLocked — учетная запись временно заблокирована после неудачных попыток входа.
+// Неверный пароль или код 2FA, после которого наступила блокировка, возвращается вместе с этой ошибкой.
+var ErrAccountLocked = errors.New("учетная запись временно заблокирована из-за неудачных попыток входа")
+
+var (
+	// ipFailuresGC — время последней очистки устаревших записей login_ip_failures этим экземпляром
+	ipFailuresMu sync.Mutex
+	ipFailuresGC time.Time
+)
+
+// IsIPLoginBlocked сообщает, превышено ли число неудачных входов с IP в текущем окне.
+// При ошибке БД вход не запрещается: ограничение по IP дополняет блокировку учетной записи, а не заменяет ее.
+func IsIPLoginBlocked(ip string) bool {
+	policy := config.LoginLockout
+	if policy.IPMaxAttempts == 0 {
+		return false
+	}
+
+	var entry models.LoginIPFailure
+	err := config.DB.Where("ip = ? AND window_start >= ?", ip, time.Now().Add(-policy.IPWindow)).First(&entry).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Log.Errorf("Ошибка при проверке неудачных входов с IP %s: %v", ip, err)
+		}
+		return false
+	}
+	return entry.Failures >= policy.IPMaxAttempts
+}
+
+// registerIPFailure учитывает неудачный вход с IP одним запросом, чтобы параллельные попытки не терялись,
+// и периодически удаляет устаревшие записи
+func registerIPFailure(ip string) error {
+	policy := config.LoginLockout
+	if policy.IPMaxAttempts == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	windowStart := now.Add(-policy.IPWindow)
+
+	ipFailuresMu.Lock()
+	cleanup := now.Sub(ipFailuresGC) > policy.IPWindow
+	if cleanup {
+		ipFailuresGC = now
+	}
+	ipFailuresMu.Unlock()
+
+	if cleanup {
+		if err := config.DB.Where("window_start < ?", windowStart).Delete(&models.LoginIPFailure{}).Error; err != nil {
+			utils.Log.Errorf("Ошибка при удалении устаревших неудачных входов по IP: %v", err)
+		}
+	}
+
+	// Окно, которое уже закончилось, начинается заново с текущей попытки
+	return config.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "ip"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr("CASE WHEN login_ip_failures.window_start < ? THEN 1 ELSE login_ip_failures.failures + 1 END", windowStart),
+			"window_start": gorm.Expr("CASE WHEN login_ip_failures.window_start < ? THEN ? ELSE login_ip_failures.window_start END",
+				windowStart, now),
+		}),
+	}).Create(&models.LoginIPFailure{IP: ip, Failures: 1, WindowStart: now}).Error
+}
+
+// lockoutDuration возвращает длительность блокировки: каждая следующая вдвое дольше предыдущей
+func lockoutDuration(previousLockouts int) time.Duration {
+	policy := config.LoginLockout
+	duration := policy.BaseDuration
+	for i := 0; i < previousLockouts && duration < policy.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > policy.MaxDuration {
+		duration = policy.MaxDuration
+	}
+	return duration
+}
+
+// RegisterFailedLogin учитывает неверный пароль или код 2FA для IP и, если пользователь найден, для учетной записи.
+// Если попытки исчерпаны, учетная запись блокируется и возвращается время окончания блокировки.
+func RegisterFailedLogin(user *models.User, ip string) (*time.Time, error) {
+	// Ошибка учета по IP не должна отменять учет по учетной записи
+	if err := registerIPFailure(ip); err != nil {
+		utils.Log.Errorf("Ошибка при учете неудачного входа с IP %s: %v", ip, err)
+	}
+
+	policy := config.LoginLockout
+	if user == nil || policy.MaxAttempts == 0 {
+		return nil, nil
+	}
+
+	var lockedUntil *time.Time
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var locked models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, user.ID).Error; err != nil {
+			return err
+		}
+
+		attempts := locked.FailedLogins + 1
+		if attempts < policy.MaxAttempts {
+			user.FailedLogins = attempts
+			return tx.Model(&locked).Update("failed_logins", attempts).Error
+		}
+
+		until := time.Now().Add(lockoutDuration(locked.LockoutCount))
+		if err := tx.Model(&locked).Updates(map[string]interface{}{
+			"failed_logins": 0,
+			"locked_until":  until,
+			"lockout_count": locked.LockoutCount + 1,
+		}).Error; err != nil {
+			return err
+		}
+
+		user.FailedLogins = 0
+		user.LockedUntil = &until
+		user.LockoutCount = locked.LockoutCount + 1
+		lockedUntil = &until
+		return nil
+	})
+
+	return lockedUntil, err
+}
+
+// ResetFailedLogins сбрасывает счетчики неудачных входов после успешного входа
+func ResetFailedLogins(user *models.User) error {
+	if user.FailedLogins == 0 && user.LockoutCount == 0 && user.LockedUntil == nil {
+		return nil
+	}
+
+	if err := config.DB.Model(user).Updates(map[string]interface{}{
+		"failed_logins": 0,
+		"locked_until":  nil,
+		"lockout_count": 0,
+	}).Error; err != nil {
+		return err
+	}
+
+	user.FailedLogins = 0
+	user.LockedUntil = nil
+	user.LockoutCount = 0
+	return nil
+}
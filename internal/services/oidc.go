@@ -0,0 +1,389 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// oauthAccessPurpose отличает access-токен стороннего клиента от access-токена самого сервиса:
+// такой токен дает доступ только к /oauth/userinfo
+const oauthAccessPurpose = "oauth_access"
+
+// PKCEMethodS256 — единственный поддерживаемый метод PKCE, plain небезопасен
+const PKCEMethodS256 = "S256"
+
+var (
+	ErrUnknownClient = errors.New("неизвестный клиент")
+	ErrInvalidClient = errors.New("ошибка аутентификации клиента")
+	ErrInvalidGrant  = errors.New("недействительный код авторизации")
+	ErrInvalidScope  = errors.New("недопустимая область доступа")
+	ErrInvalidToken  = errors.New("недействительный access-токен")
+)
+
+// SupportedScopes — области доступа, которые может запросить клиент
+var SupportedScopes = []string{models.ScopeOpenID, models.ScopeProfile, models.ScopeEmail}
+
+// OAuthTokens — ответ на обмен кода авторизации
+type OAuthTokens struct {
+	AccessToken string
+	IDToken     string
+	ExpiresIn   int64
+	Scope       string
+}
+
+// OAuthAccess — данные проверенного access-токена стороннего клиента
+type OAuthAccess struct {
+	User     models.User
+	ClientID string
+	Scope    string
+}
+
+// CreateOAuthClient регистрирует клиента. Секрет возвращается один раз, в БД хранится только его хэш.
+// Публичному клиенту секрет не выдается.
+func CreateOAuthClient(name string, redirectURIs []string, public, firstParty bool) (models.OAuthClient, string, error) {
+	clientID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return models.OAuthClient{}, "", err
+	}
+
+	client := models.OAuthClient{
+		ClientID:     clientID,
+		Name:         name,
+		RedirectURIs: redirectURIs,
+		Public:       public,
+		FirstParty:   firstParty,
+	}
+
+	var secret string
+	if !public {
+		secret, err = utils.GenerateRandomToken(32)
+		if err != nil {
+			return models.OAuthClient{}, "", err
+		}
+		client.SecretHash = utils.HashToken(secret)
+	}
+
+	if err := config.DB.Create(&client).Error; err != nil {
+		return models.OAuthClient{}, "", err
+	}
+	return client, secret, nil
+}
+
+// GetOAuthClient находит клиента по client_id
+func GetOAuthClient(clientID string) (models.OAuthClient, error) {
+	var client models.OAuthClient
+	if err := config.DB.Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return client, ErrUnknownClient
+		}
+		return client, err
+	}
+	return client, nil
+}
+
+// AuthenticateOAuthClient проверяет client_id и секрет. Публичные клиенты секрет не передают.
+func AuthenticateOAuthClient(clientID, secret string) (models.OAuthClient, error) {
+	client, err := GetOAuthClient(clientID)
+	if err != nil {
+		if errors.Is(err, ErrUnknownClient) {
+			return client, ErrInvalidClient
+		}
+		return client, err
+	}
+
+	if client.Public {
+		if secret != "" {
+			return client, ErrInvalidClient
+		}
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return client, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// NormalizeScope проверяет запрошенные области доступа и возвращает их в каноническом виде.
+// Область openid обязательна.
+func NormalizeScope(scope string) (string, error) {
+	requested := strings.Fields(scope)
+	seen := map[string]bool{}
+	var scopes []string
+
+	for _, item := range requested {
+		supported := false
+		for _, s := range SupportedScopes {
+			if s == item {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return "", ErrInvalidScope
+		}
+		if !seen[item] {
+			seen[item] = true
+			scopes = append(scopes, item)
+		}
+	}
+
+	if !seen[models.ScopeOpenID] {
+		return "", ErrInvalidScope
+	}
+
+	sort.Strings(scopes)
+	return strings.Join(scopes, " "), nil
+}
+
+// HasConsent сообщает, дал ли пользователь клиенту согласие на все запрошенные области
+func HasConsent(userID uint, clientID, scope string) (bool, error) {
+	var consent models.OAuthConsent
+	err := config.DB.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return scopeCovers(consent.Scope, scope), nil
+}
+
+// GrantConsent сохраняет согласие пользователя, объединяя его с ранее выданным
+func GrantConsent(userID uint, clientID, scope string) error {
+	var consent models.OAuthConsent
+	err := config.DB.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	merged, err := NormalizeScope(consent.Scope + " " + scope)
+	if err != nil {
+		return err
+	}
+
+	if consent.ID == 0 {
+		return config.DB.Create(&models.OAuthConsent{UserID: userID, ClientID: clientID, Scope: merged}).Error
+	}
+	return config.DB.Model(&consent).Update("scope", merged).Error
+}
+
+// CreateAuthorizationCode выдает одноразовый код авторизации, привязанный к клиенту, redirect_uri и PKCE
+func CreateAuthorizationCode(client models.OAuthClient, userID uint, redirectURI, scope, nonce, codeChallenge string) (string, error) {
+	rawCode, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	code := models.AuthorizationCode{
+		CodeHash:            utils.HashToken(rawCode),
+		ClientID:            client.ClientID,
+		UserID:              userID,
+		RedirectURI:         redirectURI,
+		Scope:               scope,
+		Nonce:               nonce,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: PKCEMethodS256,
+		ExpiresAt:           time.Now().Add(config.AuthorizationCodeTTL),
+	}
+	if err := config.DB.Create(&code).Error; err != nil {
+		return "", err
+	}
+	return rawCode, nil
+}
+
+// ExchangeAuthorizationCode обменивает код авторизации на access-токен и ID-токен.
+// Код одноразовый и действует только для клиента и redirect_uri, для которых был выдан.
+func ExchangeAuthorizationCode(client models.OAuthClient, rawCode, redirectURI, codeVerifier string) (OAuthTokens, error) {
+	var code models.AuthorizationCode
+	var user models.User
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code_hash = ?", utils.HashToken(rawCode)).
+			First(&code).Error; err != nil {
+			return ErrInvalidGrant
+		}
+
+		if code.UsedAt != nil || time.Now().After(code.ExpiresAt) {
+			return ErrInvalidGrant
+		}
+		if code.ClientID != client.ClientID || code.RedirectURI != redirectURI {
+			return ErrInvalidGrant
+		}
+		if !verifyPKCE(code.CodeChallenge, codeVerifier) {
+			return ErrInvalidGrant
+		}
+
+		if err := tx.Preload("Role").First(&user, code.UserID).Error; err != nil {
+			return ErrInvalidGrant
+		}
+		if err := CheckBan(tx, &user); err != nil {
+			return ErrInvalidGrant
+		}
+
+		return tx.Model(&code).Update("used_at", time.Now()).Error
+	})
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(config.AccessTokenTTL)
+
+	jti, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	accessToken, err := config.Keys.Sign(jwt.MapClaims{
+		"purpose":   oauthAccessPurpose,
+		"jti":       jti,
+		"iss":       config.OIDCIssuer,
+		"sub":       strconv.FormatUint(uint64(user.ID), 10),
+		"aud":       client.ClientID,
+		"client_id": client.ClientID,
+		"scope":     code.Scope,
+		"iat":       now.Unix(),
+		"iat_ms":    now.UnixMilli(),
+		"exp":       expiresAt.Unix(),
+	})
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+
+	idClaims := jwt.MapClaims{
+		"iss": config.OIDCIssuer,
+		"sub": strconv.FormatUint(uint64(user.ID), 10),
+		"aud": client.ClientID,
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+	}
+	if code.Nonce != "" {
+		idClaims["nonce"] = code.Nonce
+	}
+	for claim, value := range UserInfoClaims(user, code.Scope) {
+		idClaims[claim] = value
+	}
+
+	idToken, err := config.Keys.Sign(idClaims)
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+
+	return OAuthTokens{
+		AccessToken: accessToken,
+		IDToken:     idToken,
+		ExpiresIn:   int64(config.AccessTokenTTL.Seconds()),
+		Scope:       code.Scope,
+	}, nil
+}
+
+// ParseOAuthAccessToken проверяет access-токен, выданный стороннему клиенту, и загружает его пользователя.
+// Токен отклоняется, если он отозван, выдан другому клиенту, выдан до завершения всех сессий пользователя
+// или пользователь заблокирован.
+func ParseOAuthAccessToken(raw string) (OAuthAccess, error) {
+	token, err := config.Keys.Parse(raw)
+	if err != nil || !token.Valid {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != oauthAccessPurpose {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" || IsTokenRevoked(jti) {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	// Токен адресован клиенту, которому выдан: aud должен совпадать с client_id
+	clientID, _ := claims["client_id"].(string)
+	audience, err := claims.GetAudience()
+	if err != nil || clientID == "" || !slices.Contains(audience, clientID) {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	sub, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	var user models.User
+	if err := config.DB.Preload("Role").First(&user, userID).Error; err != nil {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+	if IssuedBeforeRevocation(claims, user.SessionsRevokedAt) {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+	if err := CheckBan(config.DB, &user); err != nil {
+		return OAuthAccess{}, ErrInvalidToken
+	}
+
+	scope, _ := claims["scope"].(string)
+	return OAuthAccess{User: user, ClientID: clientID, Scope: scope}, nil
+}
+
+// UserInfoClaims формирует claims о пользователе в соответствии с выданными областями доступа.
+// Роль включается в область profile.
+func UserInfoClaims(user models.User, scope string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": strconv.FormatUint(uint64(user.ID), 10),
+	}
+
+	scopes := strings.Fields(scope)
+	for _, s := range scopes {
+		switch s {
+		case models.ScopeProfile:
+			claims["name"] = user.Name
+			claims["updated_at"] = user.UpdatedAt.Unix()
+			if user.Role != nil {
+				claims["role"] = user.Role.Name
+			}
+		case models.ScopeEmail:
+			claims["email"] = user.Email
+			claims["email_verified"] = user.EmailVerified
+		}
+	}
+	return claims
+}
+
+// verifyPKCE сравнивает code_verifier с сохраненным code_challenge по методу S256
+func verifyPKCE(challenge, verifier string) bool {
+	if challenge == "" || verifier == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// scopeCovers сообщает, входят ли все области requested в granted
+func scopeCovers(granted, requested string) bool {
+	have := map[string]bool{}
+	for _, s := range strings.Fields(granted) {
+		have[s] = true
+	}
+	for _, s := range strings.Fields(requested) {
+		if !have[s] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/seed"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownOrganization = errors.New("организация не найдена")
+
+// TenantScope ограничивает запрос записями организации
+func TenantScope(organizationID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("organization_id = ?", organizationID)
+	}
+}
+
+// ResolveOrganization находит организацию по slug. Пустой slug означает организацию по умолчанию.
+func ResolveOrganization(slug string) (models.Organization, error) {
+	var organization models.Organization
+	if slug = strings.ToLower(strings.TrimSpace(slug)); slug == "" {
+		slug = models.DefaultOrganizationSlug
+	}
+
+	if err := config.DB.Where("slug = ?", slug).First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return organization, ErrUnknownOrganization
+		}
+		return organization, err
+	}
+	return organization, nil
+}
+
+var (
+	ErrInvalidOrganizationSlug = errors.New("slug организации может содержать только строчные латинские буквы, цифры и дефис")
+	ErrOrganizationSlugTaken   = errors.New("организация с таким slug уже существует")
+)
+
+var organizationSlug = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CreateOrganization создает организацию со встроенными ролями и ее первого администратора.
+// Администратор должен подтвердить email так же, как пользователь, созданный вручную.
+func CreateOrganization(name, slug string, registrationOpen bool, adminName, adminEmail, adminPassword string) (models.Organization, models.User, error) {
+	organization := models.Organization{Name: name, Slug: strings.ToLower(slug), RegistrationOpen: registrationOpen}
+	var admin models.User
+
+	if !organizationSlug.MatchString(organization.Slug) {
+		return organization, admin, ErrInvalidOrganizationSlug
+	}
+	if err := ValidatePassword(adminPassword, adminEmail, adminName); err != nil {
+		return organization, admin, err
+	}
+	hashedPassword, err := utils.HashPassword(adminPassword)
+	if err != nil {
+		return organization, admin, err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Organization{}).Where("slug = ?", organization.Slug).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrOrganizationSlugTaken
+		}
+
+		if err := tx.Create(&organization).Error; err != nil {
+			return err
+		}
+		if err := seed.SeedOrganization(tx, organization.ID); err != nil {
+			return err
+		}
+
+		var role models.Role
+		if err := tx.Where("organization_id = ? AND name = ?", organization.ID, models.RoleAdmin).First(&role).Error; err != nil {
+			return err
+		}
+		admin = models.User{
+			OrganizationID: organization.ID,
+			Name:           adminName,
+			Email:          adminEmail,
+			PasswordHash:   hashedPassword,
+			RoleID:         role.ID,
+		}
+		return tx.Create(&admin).Error
+	})
+	return organization, admin, err
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// personalDataMinLength — фрагменты имени и email короче этой длины не проверяются,
+// иначе пароль отклонялся бы из-за случайного совпадения пары букв
+const personalDataMinLength = 3
+
+var (
+	ErrPasswordPolicy       = errors.New("пароль не соответствует требованиям")
+	ErrWrongCurrentPassword = errors.New("неверный текущий пароль")
+)
+
+// ValidatePassword проверяет новый пароль на соответствие политике паролей.
+// email и name пользователя используются, чтобы запретить их вхождение в пароль.
+// Возвращает ErrPasswordPolicy со списком всех нарушенных требований.
+func ValidatePassword(password, email, name string) error {
+	policy := config.Password
+	var problems []string
+
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		problems = append(problems, fmt.Sprintf("длина должна быть не меньше %d символов", policy.MinLength))
+	}
+	if len(password) > policy.MaxBytes {
+		problems = append(problems, fmt.Sprintf("длина не должна превышать %d байт", policy.MaxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+	if policy.RequireUpper && !hasUpper {
+		problems = append(problems, "нужна хотя бы одна заглавная буква")
+	}
+	if policy.RequireLower && !hasLower {
+		problems = append(problems, "нужна хотя бы одна строчная буква")
+	}
+	if policy.RequireDigit && !hasDigit {
+		problems = append(problems, "нужна хотя бы одна цифра")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		problems = append(problems, "нужен хотя бы один специальный символ")
+	}
+
+	if policy.ForbidPersonalData && containsPersonalData(password, email, name) {
+		problems = append(problems, "пароль не должен содержать email или имя")
+	}
+
+	// Проверку по списку утечек делаем последней: остальные требования дешевле и понятнее пользователю
+	if len(problems) == 0 && IsPasswordBreached(password) {
+		problems = append(problems, "пароль встречается в утечках данных, выберите другой")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrPasswordPolicy, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// containsPersonalData проверяет, содержит ли пароль email, его локальную часть или имя пользователя
+func containsPersonalData(password, email, name string) bool {
+	lowered := strings.ToLower(password)
+
+	candidates := []string{email, name}
+	if local, _, found := strings.Cut(email, "@"); found {
+		candidates = append(candidates, local)
+	}
+	// Имя из нескольких слов проверяем и по отдельным словам
+	candidates = append(candidates, strings.Fields(name)...)
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if utf8.RuneCountInString(candidate) >= personalDataMinLength && strings.Contains(lowered, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangePassword меняет пароль пользователя после проверки текущего,
+// завершает все остальные сессии и выдает новую пару токенов для текущей
+func ChangePassword(userID uint, currentPassword, newPassword string, client ClientInfo) (TokenPair, models.User, error) {
+	var user models.User
+	if err := config.DB.Preload("Role").First(&user, userID).Error; err != nil {
+		return TokenPair{}, user, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return TokenPair{}, user, ErrWrongCurrentPassword
+	}
+
+	if currentPassword == newPassword {
+		return TokenPair{}, user, fmt.Errorf("%w: новый пароль совпадает с текущим", ErrPasswordPolicy)
+	}
+
+	if err := ValidatePassword(newPassword, user.Email, user.Name); err != nil {
+		return TokenPair{}, user, err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return TokenPair{}, user, err
+	}
+
+	if err := config.DB.Model(&user).Update("password_hash", hashedPassword).Error; err != nil {
+		return TokenPair{}, user, err
+	}
+
+	// Отзываем все выданные токены: остальные устройства должны войти заново с новым паролем
+	if err := RevokeAllUserSessions(user.ID); err != nil {
+		return TokenPair{}, user, err
+	}
+
+	tokens, err := IssueTokenPair(user, client)
+	return tokens, user, err
+}
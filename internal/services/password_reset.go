@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/mailer"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidResetToken = errors.New("недействительная или просроченная ссылка для сброса пароля")
+
+// RequestPasswordReset создает токен сброса пароля и отправляет ссылку на email пользователя.
+// Если пользователь не найден, ничего не делает, чтобы не раскрывать наличие учетной записи.
+func RequestPasswordReset(organizationID uint, email string) error {
+	var user models.User
+	if err := config.DB.Where("organization_id = ? AND email = ?", organizationID, email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Log.Warnf("Запрошен сброс пароля для несуществующего email: %s", email)
+			return nil
+		}
+		return err
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Действует только последняя выданная ссылка
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(rawToken),
+			ExpiresAt: time.Now().Add(config.PasswordResetTTL),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", config.AppBaseURL, url.QueryEscape(rawToken))
+	return config.Mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nДля сброса пароля перейдите по ссылке:\n%s\n\nСсылка действительна %s и может быть использована один раз.\nЕсли вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+			user.Name, link, config.PasswordResetTTL,
+		),
+	})
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса и завершает все сессии пользователя
+func ResetPassword(rawToken, newPassword string) (models.User, error) {
+	var user models.User
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.PasswordResetToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL", utils.HashToken(rawToken)).
+			First(&stored).Error; err != nil {
+			return ErrInvalidResetToken
+		}
+
+		now := time.Now()
+		if now.After(stored.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			return ErrInvalidResetToken
+		}
+
+		if err := ValidatePassword(newPassword, user.Email, user.Name); err != nil {
+			return err
+		}
+
+		hashedPassword, err := utils.HashPassword(newPassword)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&user).Update("password_hash", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&stored).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		// Старый пароль мог быть скомпрометирован, поэтому сессии завершаются в той же транзакции:
+		// пароль не меняется без отзыва сессий, а токен сброса не расходуется впустую
+		return revokeAllUserSessions(tx, user.ID, now)
+	})
+
+	return user, err
+}
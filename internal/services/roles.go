@@ -0,0 +1,13 @@
+package services
+
+import (
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+)
+
+// GetDefaultRole возвращает роль, назначаемую новым пользователям организации
+func GetDefaultRole(organizationID uint) (models.Role, error) {
+	var role models.Role
+	err := config.DB.Where("organization_id = ? AND name = ?", organizationID, models.RoleUser).First(&role).Error
+	return role, err
+}
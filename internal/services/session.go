@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval ограничивает частоту записи времени последней активности сессии
+const sessionTouchInterval = time.Minute
+
+// maxUserAgentLength — сколько символов User-Agent сохраняется в сессии
+const maxUserAgentLength = 512
+
+var ErrSessionRevoked = errors.New("сессия завершена")
+
+// ListSessions возвращает действующие сессии пользователя, начиная с последней активной
+func ListSessions(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := config.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession завершает сессию пользователя: отзывает refresh-токены ее семейства
+// и текущий access-токен. Завершенная или чужая сессия считается ненайденной.
+func RevokeSession(userID, sessionID uint) (models.Session, error) {
+	var session models.Session
+	if err := config.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		return session, err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return session, gorm.ErrRecordNotFound
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeFamily(tx, session.FamilyID, now)
+	}); err != nil {
+		return session, err
+	}
+
+	// Access-токен отклоняется и по завершенной сессии, отзыв по jti нужен, чтобы не ждать обращения к БД
+	if session.TokenID != "" {
+		if err := RevokeToken(session.TokenID, userID, now.Add(config.AccessTokenTTL)); err != nil {
+			return session, err
+		}
+	}
+
+	session.RevokedAt = &now
+	return session, nil
+}
+
+// TouchSession проверяет, что сессия access-токена действует, и обновляет время ее последней активности
+func TouchSession(sessionID, userID uint, ip string) error {
+	var session models.Session
+	if err := config.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+	return config.DB.Model(&session).Updates(map[string]interface{}{
+		"ip":           ip,
+		"last_seen_at": now,
+	}).Error
+}
+
+// revokeFamily отзывает refresh-токены семейства и завершает соответствующую сессию
+func revokeFamily(tx *gorm.DB, familyID string, now time.Time) error {
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+func truncateUserAgent(userAgent string) string {
+	runes := []rune(userAgent)
+	if len(runes) > maxUserAgentLength {
+		return string(runes[:maxUserAgentLength])
+	}
+	return userAgent
+}
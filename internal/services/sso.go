@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/sso"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// ssoStatePurpose отличает токен состояния входа через SSO от access-токена
+const ssoStatePurpose = "sso_state"
+
+var (
+	ErrUnknownSSOProvider = errors.New("неизвестный провайдер SSO")
+	ErrInvalidSSOState    = errors.New("недействительный или просроченный запрос входа через SSO")
+	ErrSSOEmailMissing    = errors.New("провайдер не передал email пользователя")
+	ErrSSOAccountConflict = errors.New("email занят учетной записью, не связанной с провайдером")
+)
+
+// SSOLoginResult описывает пользователя после входа через провайдера и изменения, внесенные по данным провайдера
+type SSOLoginResult struct {
+	User     models.User
+	Provider string
+	Created  bool
+	Linked   bool
+
+	// OldRole заполняется, если роль пользователя изменилась по группам провайдера
+	OldRole       string
+	GroupsAdded   []models.Group
+	GroupsRemoved []models.Group
+}
+
+var (
+	ssoClientsMu sync.Mutex
+	ssoClients   = map[string]*sso.Client{}
+)
+
+// ssoClient возвращает клиента провайдера. Клиенты создаются при первом обращении
+// и хранят discovery-документ и ключи провайдера между запросами.
+func ssoClient(name string) (config.SSOProvider, *sso.Client, error) {
+	provider, ok := config.SSOProviders[name]
+	if !ok {
+		return provider, nil, ErrUnknownSSOProvider
+	}
+
+	ssoClientsMu.Lock()
+	defer ssoClientsMu.Unlock()
+
+	client, ok := ssoClients[name]
+	if !ok {
+		client = sso.New(sso.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		})
+		ssoClients[name] = client
+	}
+	return provider, client, nil
+}
+
+// BeginSSOLogin возвращает адрес страницы входа провайдера и токен состояния,
+// который нужно сохранить в cookie браузера до возврата с провайдера.
+// Токен содержит state, nonce и PKCE verifier и подписан секретом сервиса.
+func BeginSSOLogin(ctx context.Context, providerName string) (authURL string, stateToken string, err error) {
+	_, client, err := ssoClient(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, challenge, err := sso.NewPKCE()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err = client.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	stateToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":  ssoStatePurpose,
+		"provider": providerName,
+		"state":    state,
+		"nonce":    nonce,
+		"verifier": verifier,
+		"iat":      now.Unix(),
+		"exp":      now.Add(config.SSOStateTTL).Unix(),
+	}).SignedString(config.JWTSecret)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, stateToken, nil
+}
+
+// CompleteSSOLogin проверяет ответ провайдера, находит или создает пользователя
+// и синхронизирует его роль и группы с группами провайдера. Для заблокированного пользователя возвращается ErrUserBanned без синхронизации
+func CompleteSSOLogin(ctx context.Context, providerName, code, state, stateToken string) (SSOLoginResult, error) {
+	result := SSOLoginResult{Provider: providerName}
+
+	provider, client, err := ssoClient(providerName)
+	if err != nil {
+		return result, err
+	}
+
+	token, err := jwt.Parse(stateToken, func(token *jwt.Token) (interface{}, error) {
+		return config.JWTSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return result, ErrInvalidSSOState
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != ssoStatePurpose || claims["provider"] != providerName {
+		return result, ErrInvalidSSOState
+	}
+	expectedState, _ := claims["state"].(string)
+	nonce, _ := claims["nonce"].(string)
+	verifier, _ := claims["verifier"].(string)
+	if expectedState == "" || state != expectedState {
+		return result, ErrInvalidSSOState
+	}
+
+	identity, err := client.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		return result, err
+	}
+
+	organization, err := ResolveOrganization(provider.Organization)
+	if err != nil {
+		return result, err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := findOrProvisionSSOUser(tx, provider, organization.ID, identity, &result); err != nil {
+			return err
+		}
+		// Данные провайдера не должны менять роль и группы заблокированного пользователя
+		if err := CheckBan(tx, &result.User); err != nil {
+			return err
+		}
+		if err := syncSSORole(tx, provider, identity, &result); err != nil {
+			return err
+		}
+		return syncSSOGroups(tx, provider, identity, &result)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err := config.DB.Preload("Role").First(&result.User, result.User.ID).Error; err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// findOrProvisionSSOUser находит пользователя по связанной учетной записи провайдера.
+// Если связи нет, учетная запись связывается с пользователем с тем же email, подтвержденным и у провайдера,
+// и в сервисе, или создается новая.
+func findOrProvisionSSOUser(tx *gorm.DB, provider config.SSOProvider, organizationID uint, identity sso.Identity, result *SSOLoginResult) error {
+	now := time.Now()
+
+	var link models.ExternalIdentity
+	err := tx.Where("provider = ? AND subject = ?", provider.Name, identity.Subject).First(&link).Error
+	if err == nil {
+		err = tx.First(&result.User, link.UserID).Error
+		if err == nil {
+			return tx.Model(&link).Updates(map[string]interface{}{"email": identity.Email, "last_login_at": now}).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Пользователь удален, связь больше ничего не значит: вход продолжается как первый
+		if err := tx.Delete(&link).Error; err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if identity.Email == "" {
+		return ErrSSOEmailMissing
+	}
+
+	err = tx.Where("organization_id = ? AND LOWER(email) = LOWER(?)", organizationID, identity.Email).First(&result.User).Error
+	switch {
+	case err == nil:
+		// Без подтверждения email у провайдера связь позволила бы захватить чужую учетную запись.
+		// Без подтверждения в сервисе учетную запись мог заранее зарегистрировать злоумышленник со своим паролем,
+		// и после связи он сохранил бы доступ к учетной записи владельца email
+		if !provider.LinkByEmail || !identity.EmailVerified || !result.User.EmailVerified {
+			return ErrSSOAccountConflict
+		}
+		result.Linked = true
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := createSSOUser(tx, provider, organizationID, identity, result); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	return tx.Create(&models.ExternalIdentity{
+		UserID:      result.User.ID,
+		Provider:    provider.Name,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: &now,
+	}).Error
+}
+
+// createSSOUser создает пользователя по данным провайдера. Пароль не задается:
+// войти по паролю можно будет только после его сброса по email.
+func createSSOUser(tx *gorm.DB, provider config.SSOProvider, organizationID uint, identity sso.Identity, result *SSOLoginResult) error {
+	roleName := mappedSSOTarget(provider.RoleMapping, identity.Strings(provider.GroupsClaim))
+	if roleName == "" {
+		roleName = provider.DefaultRole
+	}
+
+	var role models.Role
+	if err := tx.Where("organization_id = ? AND name = ?", organizationID, roleName).First(&role).Error; err != nil {
+		utils.Log.Warnf("Роль %s для провайдера SSO %s не найдена, назначается роль по умолчанию", roleName, provider.Name)
+		if role, err = GetDefaultRole(organizationID); err != nil {
+			return err
+		}
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+
+	result.User = models.User{
+		OrganizationID: organizationID,
+		Name:           utils.SanitizeInput(name),
+		Email:          identity.Email,
+		RoleID:         role.ID,
+		EmailVerified:  identity.EmailVerified,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		result.User.EmailVerifiedAt = &now
+	}
+	if err := tx.Create(&result.User).Error; err != nil {
+		return err
+	}
+
+	result.Created = true
+	return nil
+}
+
+// syncSSORole назначает роль по первому совпавшему сопоставлению групп провайдера.
+// Если совпадений нет, роль, ранее полученная через сопоставление, заменяется ролью по умолчанию,
+// а роль, назначенная вручную, сохраняется.
+func syncSSORole(tx *gorm.DB, provider config.SSOProvider, identity sso.Identity, result *SSOLoginResult) error {
+	if len(provider.RoleMapping) == 0 {
+		return nil
+	}
+
+	var current models.Role
+	if err := tx.First(&current, result.User.RoleID).Error; err != nil {
+		return err
+	}
+
+	target := mappedSSOTarget(provider.RoleMapping, identity.Strings(provider.GroupsClaim))
+	if target == "" {
+		for _, mapping := range provider.RoleMapping {
+			if mapping.Target == current.Name {
+				target = provider.DefaultRole
+				break
+			}
+		}
+	}
+	if target == "" || target == current.Name {
+		return nil
+	}
+
+	var role models.Role
+	if err := tx.Where("organization_id = ? AND name = ?", result.User.OrganizationID, target).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Log.Warnf("Роль %s из сопоставления провайдера SSO %s не найдена", target, provider.Name)
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Model(&result.User).Update("role_id", role.ID).Error; err != nil {
+		return err
+	}
+	if !result.Created {
+		result.OldRole = current.Name
+	}
+	return nil
+}
+
+// syncSSOGroups приводит членство в сопоставленных группах в соответствие с группами провайдера.
+// Членство в группах, не упомянутых в сопоставлении, не меняется. Недостающие группы создаются.
+// Исключение подчиняется тем же правилам, что и через API: последний владелец остается в группе.
+func syncSSOGroups(tx *gorm.DB, provider config.SSOProvider, identity sso.Identity, result *SSOLoginResult) error {
+	if len(provider.GroupMapping) == 0 {
+		return nil
+	}
+
+	claimed := map[string]bool{}
+	for _, value := range identity.Strings(provider.GroupsClaim) {
+		claimed[value] = true
+	}
+
+	managed := map[string]bool{}
+	wanted := map[string]bool{}
+	for _, mapping := range provider.GroupMapping {
+		managed[mapping.Target] = true
+		if claimed[mapping.Claim] {
+			wanted[mapping.Target] = true
+		}
+	}
+
+	var current []models.Group
+	if err := tx.Model(&result.User).Association("Groups").Find(&current); err != nil {
+		return err
+	}
+	member := map[string]bool{}
+	for _, group := range current {
+		member[group.Name] = true
+		if managed[group.Name] && !wanted[group.Name] {
+			if _, err := removeGroupMember(tx, group.ID, result.User.ID, true); err != nil {
+				if errors.Is(err, ErrLastGroupOwner) {
+					utils.Log.Warnf("Пользователь ID=%d остается в группе %s через SSO: он ее последний владелец", result.User.ID, group.Name)
+					continue
+				}
+				return err
+			}
+			result.GroupsRemoved = append(result.GroupsRemoved, group)
+		}
+	}
+
+	for _, mapping := range provider.GroupMapping {
+		name := mapping.Target
+		if !wanted[name] || member[name] {
+			continue
+		}
+
+		group := models.Group{OrganizationID: result.User.OrganizationID, Name: name}
+		if err := tx.Where("organization_id = ? AND name = ?", group.OrganizationID, name).FirstOrCreate(&group).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&result.User).Association("Groups").Append(&group); err != nil {
+			return err
+		}
+		member[name] = true
+		result.GroupsAdded = append(result.GroupsAdded, group)
+	}
+
+	return nil
+}
+
+// mappedSSOTarget возвращает цель первого сопоставления, значение которого есть среди групп пользователя
+func mappedSSOTarget(mappings []config.ClaimMapping, groups []string) string {
+	for _, mapping := range mappings {
+		for _, group := range groups {
+			if group == mapping.Claim {
+				return mapping.Target
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("невалидный refresh-токен")
+	ErrRefreshTokenReused  = errors.New("повторное использование refresh-токена")
+)
+
+// TokenPair — пара токенов, выдаваемая при входе и при обновлении
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+// ClientInfo — данные устройства, с которого выполняется вход, сохраняются в сессии
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// GenerateAccessToken создает короткоживущий JWT для пользователя, подписанный активным ключом.
+// Уникальный jti позволяет отозвать конкретный токен до истечения его срока, а sid связывает токен с сессией.
+func GenerateAccessToken(user models.User, sessionID uint) (token string, jti string, err error) {
+	jti, err = utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	token, err = config.Keys.Sign(jwt.MapClaims{
+		"jti":    jti,
+		"sid":    sessionID,
+		"org":    user.OrganizationID,
+		"userID": user.ID,
+		"role":   user.Role,
+		"iat":    now.Unix(),
+		"iat_ms": now.UnixMilli(),
+		"exp":    now.Add(config.AccessTokenTTL).Unix(),
+	})
+	return token, jti, err
+}
+
+// IssueTokenPair начинает новую сессию: выдает access-токен и refresh-токен нового семейства
+func IssueTokenPair(user models.User, client ClientInfo) (TokenPair, error) {
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	var (
+		refreshToken string
+		session      models.Session
+	)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		var err error
+		refreshToken, stored, err = createRefreshToken(tx, user.ID, familyID)
+		if err != nil {
+			return err
+		}
+
+		session = models.Session{
+			UserID:     user.ID,
+			FamilyID:   familyID,
+			IP:         client.IP,
+			UserAgent:  truncateUserAgent(client.UserAgent),
+			LastSeenAt: time.Now(),
+			ExpiresAt:  stored.ExpiresAt,
+		}
+		return tx.Create(&session).Error
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return buildTokenPair(user, &session, refreshToken)
+}
+
+// RotateRefreshToken обменивает refresh-токен на новую пару токенов и продлевает сессию.
+// Повторное предъявление уже использованного токена отзывает все семейство и завершает сессию.
+func RotateRefreshToken(rawToken string, client ClientInfo) (TokenPair, models.User, error) {
+	var (
+		user        models.User
+		session     models.Session
+		newRawToken string
+		reused      bool
+	)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", utils.HashToken(rawToken)).
+			First(&stored).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		now := time.Now()
+
+		// Токен уже был использован или отозван — считаем это кражей и отзываем все семейство
+		if stored.RevokedAt != nil || stored.ReplacedByID != nil {
+			reused = true
+			return revokeFamily(tx, stored.FamilyID, now)
+		}
+
+		if now.After(stored.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.Preload("Role").First(&user, stored.UserID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := CheckBan(tx, &user); err != nil {
+			return err
+		}
+
+		if err := CheckEmailVerified(&user); err != nil {
+			return err
+		}
+
+		rawNext, next, err := createRefreshToken(tx, stored.UserID, stored.FamilyID)
+		if err != nil {
+			return err
+		}
+		newRawToken = rawNext
+
+		if err := tx.Model(&stored).Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"replaced_by_id": next.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		// У входов, выполненных до появления сессий, строки сессии нет — она создается при первом обновлении
+		if err := tx.Where(models.Session{FamilyID: stored.FamilyID}).
+			Attrs(models.Session{UserID: stored.UserID}).
+			FirstOrCreate(&session).Error; err != nil {
+			return err
+		}
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"ip":           client.IP,
+			"user_agent":   truncateUserAgent(client.UserAgent),
+			"last_seen_at": now,
+			"expires_at":   next.ExpiresAt,
+		}).Error
+	})
+
+	if reused {
+		utils.Log.Warn("Обнаружено повторное использование refresh-токена, семейство отозвано")
+		return TokenPair{}, models.User{}, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return TokenPair{}, models.User{}, err
+	}
+
+	pair, err := buildTokenPair(user, &session, newRawToken)
+	return pair, user, err
+}
+
+// createRefreshToken сохраняет хэш нового refresh-токена и возвращает сам токен
+func createRefreshToken(db *gorm.DB, userID uint, familyID string) (string, models.RefreshToken, error) {
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", models.RefreshToken{}, err
+	}
+
+	stored := models.RefreshToken{
+		UserID:    userID,
+		TokenHash: utils.HashToken(rawToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(config.RefreshTokenTTL),
+	}
+	if err := db.Create(&stored).Error; err != nil {
+		return "", models.RefreshToken{}, err
+	}
+
+	return rawToken, stored, nil
+}
+
+// buildTokenPair выдает access-токен сессии и запоминает его jti в сессии
+func buildTokenPair(user models.User, session *models.Session, refreshToken string) (TokenPair, error) {
+	accessToken, jti, err := GenerateAccessToken(user, session.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := config.DB.Model(session).Update("token_id", jti).Error; err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(config.AccessTokenTTL.Seconds()),
+	}, nil
+}
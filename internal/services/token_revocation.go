@@ -0,0 +1,141 @@
+package services
+
+import (
+	"sync"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// revokedTokens — кэш таблицы revoked_tokens: jti -> время истечения токена
+var revokedTokens sync.Map
+
+// StartRevocationSync загружает отозванные токены в кэш и периодически синхронизирует его с БД,
+// чтобы отзывы, сделанные другими экземплярами сервиса, тоже учитывались
+func StartRevocationSync(interval time.Duration) {
+	if err := syncRevokedTokens(); err != nil {
+		utils.Log.Errorf("Не удалось загрузить список отозванных токенов: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := syncRevokedTokens(); err != nil {
+				utils.Log.Errorf("Ошибка синхронизации отозванных токенов: %v", err)
+			}
+		}
+	}()
+}
+
+func syncRevokedTokens() error {
+	now := time.Now()
+
+	// Истекшие токены больше не нужно хранить: они и так не пройдут проверку
+	if err := config.DB.Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	var tokens []models.RevokedToken
+	if err := config.DB.Find(&tokens).Error; err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		revokedTokens.Store(token.JTI, token.ExpiresAt)
+	}
+
+	revokedTokens.Range(func(key, value interface{}) bool {
+		if now.After(value.(time.Time)) {
+			revokedTokens.Delete(key)
+		}
+		return true
+	})
+
+	return nil
+}
+
+// RevokeToken отзывает access-токен до истечения его срока действия
+func RevokeToken(jti string, userID uint, expiresAt time.Time) error {
+	token := models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+	if err := config.DB.Where(models.RevokedToken{JTI: jti}).FirstOrCreate(&token).Error; err != nil {
+		return err
+	}
+
+	revokedTokens.Store(jti, expiresAt)
+	return nil
+}
+
+// IsTokenRevoked проверяет, отозван ли access-токен
+func IsTokenRevoked(jti string) bool {
+	_, revoked := revokedTokens.Load(jti)
+	return revoked
+}
+
+// IssuedBeforeRevocation сообщает, что токен выдан до момента revokedAt завершения всех сессий.
+// Стандартный iat хранит только секунды, поэтому время выдачи берется из claim iat_ms в миллисекундах:
+// иначе токен, украденный в ту же секунду, пережил бы завершение сессий.
+func IssuedBeforeRevocation(claims jwt.MapClaims, revokedAt *time.Time) bool {
+	if revokedAt == nil {
+		return false
+	}
+	if issuedMs, ok := claims["iat_ms"].(float64); ok {
+		return int64(issuedMs) < revokedAt.UnixMilli()
+	}
+
+	// Токены, выданные до появления iat_ms: выданный в ту же секунду считается выданным до завершения сессий
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return true
+	}
+	return issuedAt.Unix() <= revokedAt.Unix()
+}
+
+// RevokeAllUserSessions завершает все сессии пользователя: отзывает refresh-токены
+// и делает недействительными все access-токены, выданные до текущего момента
+func RevokeAllUserSessions(userID uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeAllUserSessions(tx, userID, time.Now())
+	})
+}
+
+// revokeAllUserSessions завершает все сессии пользователя в рамках переданной транзакции
+func revokeAllUserSessions(tx *gorm.DB, userID uint, now time.Time) error {
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("sessions_revoked_at", now).Error
+}
+
+// RevokeRefreshTokenFamily отзывает все refresh-токены входа, к которому относится переданный токен, и завершает его сессию
+func RevokeRefreshTokenFamily(rawToken string, userID uint) error {
+	var stored models.RefreshToken
+	if err := config.DB.Where("token_hash = ? AND user_id = ?", utils.HashToken(rawToken), userID).
+		First(&stored).Error; err != nil {
+		return ErrInvalidRefreshToken
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeFamily(tx, stored.FamilyID, time.Now())
+	})
+}
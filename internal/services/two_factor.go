@@ -0,0 +1,358 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"userManagement/internal/config"
+	"userManagement/internal/models"
+	"userManagement/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// twoFactorChallengePurpose отличает токен второго шага входа от access-токена
+	twoFactorChallengePurpose = "2fa_challenge"
+	// twoFactorMaxAttempts — число неверных кодов, после которого токен второго шага аннулируется.
+	// Счетчик хранится в строке пользователя и сбрасывается при выдаче нового токена и успешном входе
+	twoFactorMaxAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("двухфакторная аутентификация уже включена")
+	ErrTwoFactorNotEnabled     = errors.New("двухфакторная аутентификация не включена")
+	ErrTwoFactorNotSetUp       = errors.New("сначала начните настройку двухфакторной аутентификации")
+	ErrTwoFactorRequired       = errors.New("двухфакторная аутентификация обязательна для роли пользователя")
+	ErrInvalidTwoFactorCode    = errors.New("неверный код подтверждения")
+	ErrInvalidChallenge        = errors.New("недействительный или просроченный токен второго шага входа")
+)
+
+// TwoFactorRequired сообщает, что роль пользователя требует 2FA, а пользователь ее еще не настроил.
+// Роль должна быть предварительно загружена.
+func TwoFactorRequired(user *models.User) bool {
+	return user.Role != nil && user.Role.Require2FA && !user.TwoFactorEnabled
+}
+
+// BeginTwoFactorSetup создает новый секрет TOTP. 2FA включается только после подтверждения кодом.
+func BeginTwoFactorSetup(userID uint) (secret string, uri string, err error) {
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		return "", "", err
+	}
+	if user.TwoFactorEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err = generateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := config.DB.Model(&user).Updates(map[string]interface{}{
+		"two_factor_secret":    secret,
+		"two_factor_last_step": 0,
+	}).Error; err != nil {
+		return "", "", err
+	}
+
+	return secret, totpURI(config.TOTPIssuer, user.Email, secret), nil
+}
+
+// ConfirmTwoFactor включает 2FA, если код из приложения совпал с новым секретом,
+// и возвращает коды восстановления. Коды показываются пользователю один раз.
+func ConfirmTwoFactor(userID uint, code string) ([]string, error) {
+	var codes []string
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		if user.TwoFactorSecret == "" {
+			return ErrTwoFactorNotSetUp
+		}
+
+		step, ok := validateTOTP(user.TwoFactorSecret, normalizeTwoFactorCode(code), user.TwoFactorLastStep, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	return codes, err
+}
+
+// DisableTwoFactor отключает 2FA после проверки пароля и кода.
+// Если роль пользователя требует 2FA, отключить ее нельзя.
+func DisableTwoFactor(userID uint, password, code string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		var role models.Role
+		if err := tx.First(&role, user.RoleID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return ErrTwoFactorNotEnabled
+		}
+		if role.Require2FA {
+			return ErrTwoFactorRequired
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+			return ErrWrongCurrentPassword
+		}
+
+		if _, err := verifySecondFactor(tx, &user, code); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+		}).Error
+	})
+}
+
+// RegenerateRecoveryCodes заменяет коды восстановления новыми после проверки кода
+func RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	var codes []string
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return ErrTwoFactorNotEnabled
+		}
+
+		if _, err := verifySecondFactor(tx, &user, code); err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	return codes, err
+}
+
+// CreateTwoFactorChallenge выдает токен второго шага входа после успешной проверки пароля
+func CreateTwoFactorChallenge(user models.User) (string, int64, error) {
+	jti, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := config.DB.Model(&user).Update("two_factor_failures", 0).Error; err != nil {
+		return "", 0, err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose": twoFactorChallengePurpose,
+		"jti":     jti,
+		"userID":  user.ID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(config.TwoFactorChallengeTTL).Unix(),
+	})
+
+	signed, err := token.SignedString(config.JWTSecret)
+	if err != nil {
+		return "", 0, err
+	}
+	return signed, int64(config.TwoFactorChallengeTTL.Seconds()), nil
+}
+
+// CompleteTwoFactorLogin проверяет токен второго шага и код (TOTP или код восстановления)
+// и выдает пару токенов. Токен второго шага одноразовый.
+// Неверные коды учитываются в блокировке учетной записи так же, как неверные пароли,
+// а счетчик неудачных входов сбрасывается только после успешного второго шага.
+// usedRecovery сообщает, что для входа был использован код восстановления.
+func CompleteTwoFactorLogin(challenge, code string, client ClientInfo) (tokens TokenPair, user models.User, usedRecovery bool, err error) {
+	token, err := jwt.Parse(challenge, func(token *jwt.Token) (interface{}, error) {
+		return config.JWTSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return tokens, user, false, ErrInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != twoFactorChallengePurpose {
+		return tokens, user, false, ErrInvalidChallenge
+	}
+	userID, okID := claims["userID"].(float64)
+	jti, okJTI := claims["jti"].(string)
+	if !okID || !okJTI || IsTokenRevoked(jti) {
+		return tokens, user, false, ErrInvalidChallenge
+	}
+	expiresAt, _ := claims.GetExpirationTime()
+
+	if err := config.DB.Preload("Role").First(&user, uint(userID)).Error; err != nil {
+		return tokens, user, false, ErrInvalidChallenge
+	}
+	if !user.TwoFactorEnabled {
+		return tokens, user, false, ErrInvalidChallenge
+	}
+	if err := CheckBan(config.DB, &user); err != nil {
+		return tokens, user, false, err
+	}
+	if err := CheckEmailVerified(&user); err != nil {
+		return tokens, user, false, err
+	}
+	if user.IsLocked(time.Now()) {
+		return tokens, user, false, ErrAccountLocked
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var verifyErr error
+		usedRecovery, verifyErr = verifySecondFactor(tx, &user, code)
+		if verifyErr != nil {
+			return verifyErr
+		}
+		return tx.Model(&user).Update("two_factor_failures", 0).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			exhausted, countErr := registerChallengeFailure(user.ID)
+			if countErr != nil {
+				utils.Log.Errorf("Ошибка при учете неверного кода 2FA пользователя ID=%d: %v", user.ID, countErr)
+			}
+			if exhausted {
+				utils.Log.Warnf("Превышено число попыток ввода кода 2FA пользователем ID=%d", user.ID)
+			}
+
+			lockedUntil, lockErr := RegisterFailedLogin(&user, client.IP)
+			if lockErr != nil {
+				utils.Log.Errorf("Ошибка при учете неудачного входа пользователя ID=%d: %v", user.ID, lockErr)
+			}
+
+			if exhausted || lockedUntil != nil {
+				if revokeErr := RevokeToken(jti, user.ID, expiresAt.Time); revokeErr != nil {
+					utils.Log.Errorf("Не удалось аннулировать токен второго шага: %v", revokeErr)
+				}
+			}
+			if lockedUntil != nil {
+				return tokens, user, false, fmt.Errorf("%w: %w", ErrInvalidTwoFactorCode, ErrAccountLocked)
+			}
+		}
+		return tokens, user, false, err
+	}
+
+	if err := ResetFailedLogins(&user); err != nil {
+		utils.Log.Errorf("Не удалось сбросить счетчик неудачных входов пользователя ID=%d: %v", user.ID, err)
+	}
+
+	if err := RevokeToken(jti, user.ID, expiresAt.Time); err != nil {
+		return tokens, user, false, err
+	}
+
+	tokens, err = IssueTokenPair(user, client)
+	return tokens, user, usedRecovery, err
+}
+
+// verifySecondFactor проверяет код из приложения или одноразовый код восстановления.
+// Вызывается внутри транзакции: строка пользователя блокируется, чтобы код нельзя было использовать дважды.
+func verifySecondFactor(tx *gorm.DB, user *models.User, code string) (usedRecovery bool, err error) {
+	var locked models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, user.ID).Error; err != nil {
+		return false, err
+	}
+
+	code = normalizeTwoFactorCode(code)
+
+	if step, ok := validateTOTP(locked.TwoFactorSecret, code, locked.TwoFactorLastStep, time.Now()); ok {
+		return false, tx.Model(&locked).Update("two_factor_last_step", step).Error
+	}
+
+	var recovery models.RecoveryCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashToken(code)).
+		First(&recovery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrInvalidTwoFactorCode
+		}
+		return false, err
+	}
+
+	return true, tx.Model(&recovery).Update("used_at", time.Now()).Error
+}
+
+// replaceRecoveryCodes удаляет старые коды восстановления и создает новые
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(code)})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeTwoFactorCode убирает пробелы и дефисы, которые пользователи вводят вместе с кодом
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// registerChallengeFailure увеличивает счетчик неверных кодов второго шага и сообщает, исчерпаны ли попытки.
+// Счетчик хранится в БД, поэтому ограничение действует при нескольких экземплярах сервиса.
+func registerChallengeFailure(userID uint) (bool, error) {
+	exhausted := false
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var locked models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, userID).Error; err != nil {
+			return err
+		}
+
+		failures := locked.TwoFactorFailures + 1
+		if failures >= twoFactorMaxAttempts {
+			exhausted = true
+			failures = 0
+		}
+		return tx.Model(&locked).Update("two_factor_failures", failures).Error
+	})
+	return exhausted, err
+}
@@ -0,0 +1,386 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"userManagement/internal/keyring"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksRefreshInterval ограничивает повторную загрузку ключей провайдера при встрече незнакомого kid
+const jwksRefreshInterval = time.Minute
+
+var (
+	ErrProvider       = errors.New("ошибка обращения к провайдеру")
+	ErrInvalidIDToken = errors.New("недействительный ID-токен провайдера")
+)
+
+// Config — параметры подключения к внешнему провайдеру OpenID Connect
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// metadata — нужная часть discovery-документа провайдера
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type verificationKey struct {
+	public interface{}
+	method jwt.SigningMethod
+}
+
+// Identity — проверенные данные пользователя из ID-токена провайдера
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Claims        jwt.MapClaims
+}
+
+// Strings возвращает значение claim как список строк. Провайдеры передают группы
+// массивом или, реже, одной строкой.
+func (i Identity) Strings(claim string) []string {
+	switch value := i.Claims[claim].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Client выполняет authorization code flow с PKCE у внешнего провайдера.
+// Discovery-документ и ключи провайдера загружаются при первом обращении и кэшируются.
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu          sync.Mutex
+	meta        *metadata
+	keys        map[string]verificationKey
+	keysFetched time.Time
+}
+
+// New создает клиента провайдера
+func New(cfg Config) *Client {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &Client{cfg: cfg, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewPKCE возвращает случайный code_verifier и вычисленный из него code_challenge (S256)
+func NewPKCE() (verifier, challenge string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: некорректный authorization_endpoint: %v", ErrProvider, err)
+	}
+
+	query := target.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.cfg.ClientID)
+	query.Set("redirect_uri", c.cfg.RedirectURL)
+	query.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+// Exchange обменивает код авторизации на токены и возвращает данные пользователя из проверенного ID-токена.
+// Если провайдер не включил email в ID-токен, он запрашивается через userinfo.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {c.cfg.ClientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+
+	var tokens struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := c.doJSON(req, &tokens)
+	if err != nil {
+		return Identity{}, err
+	}
+	if status != http.StatusOK || tokens.IDToken == "" {
+		return Identity{}, fmt.Errorf("%w: token endpoint вернул %d %s %s", ErrProvider, status, tokens.Error, tokens.ErrorDescription)
+	}
+
+	identity, err := c.verifyIDToken(ctx, meta, tokens.IDToken, nonce)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if identity.Email == "" && meta.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := c.mergeUserInfo(ctx, meta, tokens.AccessToken, &identity); err != nil {
+			return Identity{}, err
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken проверяет подпись, издателя, получателя, срок действия и nonce ID-токена
+func (c *Client) verifyIDToken(ctx context.Context, meta *metadata, raw, nonce string) (Identity, error) {
+	keyfunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := c.key(ctx, meta, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("алгоритм %s не соответствует ключу %q", token.Method.Alg(), kid)
+		}
+		return key.public, nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return Identity{}, fmt.Errorf("%w: nonce не совпадает", ErrInvalidIDToken)
+	}
+
+	identity := Identity{Claims: claims}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: нет claim sub", ErrInvalidIDToken)
+	}
+	fillProfile(&identity, claims)
+
+	return identity, nil
+}
+
+// mergeUserInfo дополняет данные пользователя ответом userinfo того же субъекта
+func (c *Client) mergeUserInfo(ctx context.Context, meta *metadata, accessToken string, identity *Identity) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.UserinfoEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	claims := jwt.MapClaims{}
+	status, err := c.doJSON(req, &claims)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("%w: userinfo вернул %d", ErrProvider, status)
+	}
+	if sub, _ := claims["sub"].(string); sub != identity.Subject {
+		return fmt.Errorf("%w: userinfo вернул другого пользователя", ErrProvider)
+	}
+
+	for name, value := range claims {
+		if _, ok := identity.Claims[name]; !ok {
+			identity.Claims[name] = value
+		}
+	}
+	fillProfile(identity, identity.Claims)
+	return nil
+}
+
+// fillProfile извлекает email, признак его подтверждения и имя пользователя
+func fillProfile(identity *Identity, claims jwt.MapClaims) {
+	identity.Email, _ = claims["email"].(string)
+
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	identity.Name, _ = claims["name"].(string)
+	if identity.Name == "" {
+		identity.Name, _ = claims["preferred_username"].(string)
+	}
+}
+
+// metadata загружает discovery-документ провайдера
+func (c *Client) metadata(ctx context.Context) (*metadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meta != nil {
+		return c.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta metadata
+	status, err := c.doJSON(req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: discovery-документ вернул %d", ErrProvider, status)
+	}
+
+	// Документ должен принадлежать тому же издателю, иначе подмена discovery позволила бы подставить чужие ключи
+	if strings.TrimSuffix(meta.Issuer, "/") != c.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q в discovery-документе не совпадает с %q", ErrProvider, meta.Issuer, c.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: в discovery-документе нет обязательных адресов", ErrProvider)
+	}
+
+	c.meta = &meta
+	return c.meta, nil
+}
+
+// key возвращает ключ проверки подписи по kid. Незнакомый kid означает, что провайдер сменил ключи,
+// поэтому набор ключей загружается заново, но не чаще jwksRefreshInterval.
+func (c *Client) key(ctx context.Context, meta *metadata, kid string) (verificationKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.lookupKey(kid); ok {
+		return key, nil
+	}
+	if c.keys != nil && time.Since(c.keysFetched) < jwksRefreshInterval {
+		return verificationKey{}, fmt.Errorf("неизвестный ключ %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.JWKSURI, nil)
+	if err != nil {
+		return verificationKey{}, err
+	}
+
+	var set keyring.JWKSet
+	status, err := c.doJSON(req, &set)
+	if err != nil {
+		return verificationKey{}, err
+	}
+	if status != http.StatusOK {
+		return verificationKey{}, fmt.Errorf("%w: jwks_uri вернул %d", ErrProvider, status)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		public, method, err := jwk.PublicKey()
+		if err != nil {
+			// Ключи неподдерживаемых типов пропускаются: токен может быть подписан другим ключом набора
+			continue
+		}
+		keys[jwk.Kid] = verificationKey{public: public, method: method}
+	}
+	c.keys = keys
+	c.keysFetched = time.Now()
+
+	if key, ok := c.lookupKey(kid); ok {
+		return key, nil
+	}
+	return verificationKey{}, fmt.Errorf("неизвестный ключ %q", kid)
+}
+
+// lookupKey ищет ключ в кэше. Токен без kid допустим, только если у провайдера единственный ключ.
+func (c *Client) lookupKey(kid string) (verificationKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// doJSON выполняет запрос и разбирает JSON-ответ. Тело ответа ограничено 1 МБ.
+func (c *Client) doJSON(req *http.Request, target interface{}) (int, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	if err := json.Unmarshal(body, target); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("%w: некорректный JSON от %s: %v", ErrProvider, req.URL.Host, err)
+	}
+	return resp.StatusCode, nil
+}